@@ -0,0 +1,48 @@
+package negacyclic
+
+// Automorphism returns p(X^k) in Z[X]/(X^N+1), for an odd k. The map
+// X -> X^k is a ring automorphism, which sends the coefficient of X^i to
+// X^{ik mod 2N} = ±X^{ik mod N}.
+func (p *Polynomial) Automorphism(k int) *Polynomial {
+	n := p.Deg()
+	res := NewPolynomial(n)
+	for i, coeff := range p.Coeffs {
+		index, neg := automorphismIndex(i, k, n)
+		res.Coeffs[index].Set(coeff)
+		if neg {
+			res.Coeffs[index].Neg(res.Coeffs[index])
+		}
+	}
+	return res
+}
+
+// Automorphism returns v(X^k) in Z[X]/(X^N+1), for an odd k, see
+// Polynomial.Automorphism.
+func (v *Vector) Automorphism(k int) *Vector {
+	n := v.Len()
+	res := NewVector(n)
+	for i, coeff := range v.Coeffs {
+		index, neg := automorphismIndex(i, k, n)
+		if neg {
+			coeff = -coeff
+		}
+		res.Coeffs[index] = coeff
+	}
+	return res
+}
+
+// automorphismIndex returns the index and the sign of the image of X^i by
+// X -> X^k.
+func automorphismIndex(i, k, n int) (int, bool) {
+	if k%2 == 0 {
+		panic("automorphism expects an odd exponent")
+	}
+	index := (i * k) % (2 * n)
+	if index < 0 {
+		index += 2 * n
+	}
+	if index >= n {
+		return index - n, true
+	}
+	return index, false
+}
@@ -0,0 +1,75 @@
+package negacyclic
+
+import "math/big"
+
+// BatchEncoder packs N integers modulo a prime t into a polynomial of
+// Z_t[X]/(X^N+1), using the CRT isomorphism Z_t[X]/(X^N+1) ≅ Z_t^N given by
+// the evaluation at the primitive 2N-th roots of unity modulo t. It expects
+// t = 1 mod 2N.
+//
+// The N slots are arranged as a 2 × (N/2) matrix: slot j of the first row is
+// the evaluation at ψ^{5^j}, and slot j of the second row is the evaluation
+// at ψ^{-5^j}, where ψ is the primitive root used by the NTT. Hence the
+// automorphism X -> X^5 rotates both rows by one position, and X -> X^{-1}
+// swaps the rows.
+type BatchEncoder struct {
+	N          int
+	T          *big.Int
+	multiplier *Multiplier
+	// slots[i] is the index in the output of NTT of the evaluation defining
+	// slot i.
+	slots []int
+}
+
+// NewBatchEncoder creates and returns a BatchEncoder for N slots modulo the
+// plaintext prime t.
+func NewBatchEncoder(n int, t *big.Int) *BatchEncoder {
+	if !t.IsUint64() {
+		panic("batch encoder expects a 64-bit plaintext modulus")
+	}
+	enc := new(BatchEncoder)
+	enc.N = n
+	enc.T = t
+	enc.multiplier = NewMultiplier(n, t)
+	enc.slots = make([]int, n)
+	// NTT stores the evaluation at ψ^{2 brv(i) + 1} at index i.
+	pow := 1
+	for j := 0; j < n/2; j++ {
+		enc.slots[j] = int(reverseBits((pow-1)/2, n))
+		enc.slots[n/2+j] = int(reverseBits((2*n-pow-1)/2, n))
+		pow = (5 * pow) % (2 * n)
+	}
+	return enc
+}
+
+// Encode returns the polynomial with coefficients in [0, t) whose slots hold
+// the given values modulo t. If less than N values are given, the remaining
+// slots are set to zero.
+func (enc *BatchEncoder) Encode(values []uint64) *Polynomial {
+	if len(values) > enc.N {
+		panic("too many values to encode")
+	}
+	p := NewPolynomial(enc.N)
+	for i, val := range values {
+		p.Coeffs[enc.slots[i]].SetUint64(val).Mod(p.Coeffs[enc.slots[i]], enc.T)
+	}
+	enc.multiplier.INTT(p)
+	return p
+}
+
+// Decode returns the N slots of p modulo t. It does not modify p.
+func (enc *BatchEncoder) Decode(p *Polynomial) []uint64 {
+	if p.Deg() != enc.N {
+		panic("polynomial of unexpected degree")
+	}
+	a := NewPolynomial(enc.N)
+	for i, coeff := range p.Coeffs {
+		a.Coeffs[i].Mod(coeff, enc.T)
+	}
+	enc.multiplier.NTT(a)
+	values := make([]uint64, enc.N)
+	for i, index := range enc.slots {
+		values[i] = a.Coeffs[index].Uint64()
+	}
+	return values
+}
@@ -0,0 +1,74 @@
+package negacyclic_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"negacyclic"
+)
+
+func TestBatchEncoder(t *testing.T) {
+	t.Run("roundtrip", testBatchRoundtrip)
+	t.Run("slotwise_product", testBatchProduct)
+	t.Run("rotation", testBatchRotation)
+}
+
+func testBatchRoundtrip(t *testing.T) {
+	n := 1 << 10
+	tMod := big.NewInt(12289)
+	enc := negacyclic.NewBatchEncoder(n, tMod)
+	values := randomSlots(n, tMod.Uint64())
+	got := enc.Decode(enc.Encode(values))
+	for i := range values {
+		if got[i] != values[i] {
+			t.Fatalf("slot %d: expected %d, got %d", i, values[i], got[i])
+		}
+	}
+}
+
+func testBatchProduct(t *testing.T) {
+	n := 1 << 8
+	tMod := negacyclic.RLWEPrime(40, 2*n)
+	enc := negacyclic.NewBatchEncoder(n, tMod)
+	x, y := randomSlots(n, tMod.Uint64()), randomSlots(n, tMod.Uint64())
+	prod := naive(enc.Encode(x), enc.Encode(y), tMod)
+	got := enc.Decode(prod)
+	for i := range got {
+		expected := new(big.Int).SetUint64(x[i])
+		expected.Mul(expected, new(big.Int).SetUint64(y[i])).Mod(expected, tMod)
+		if got[i] != expected.Uint64() {
+			t.Fatalf("slot %d: expected %d, got %d", i, expected, got[i])
+		}
+	}
+}
+
+func testBatchRotation(t *testing.T) {
+	n := 1 << 6
+	tMod := big.NewInt(257)
+	enc := negacyclic.NewBatchEncoder(n, tMod)
+	values := randomSlots(n, tMod.Uint64())
+	p := enc.Encode(values)
+
+	rotated := enc.Decode(p.Automorphism(5))
+	for j := 0; j < n/2; j++ {
+		next := (j + 1) % (n / 2)
+		if rotated[j] != values[next] || rotated[n/2+j] != values[n/2+next] {
+			t.Fatalf("X -> X^5 does not rotate slot %d", j)
+		}
+	}
+	swapped := enc.Decode(p.Automorphism(-1))
+	for j := 0; j < n/2; j++ {
+		if swapped[j] != values[n/2+j] || swapped[n/2+j] != values[j] {
+			t.Fatalf("X -> X^{-1} does not swap slot %d", j)
+		}
+	}
+}
+
+func randomSlots(n int, t uint64) []uint64 {
+	values := make([]uint64, n)
+	for i := range values {
+		values[i] = rand.Uint64() % t
+	}
+	return values
+}
@@ -0,0 +1,185 @@
+// Package bfv implements the somewhat homomorphic encryption scheme of
+// Brakerski, Fan and Vercauteren (BFV) over the negacyclic ring
+// R_q = Z_q[X]/(X^N+1), for a prime q = 1 mod 2N and a plaintext modulus t.
+//
+// A plaintext m in R_t is encrypted as an RLWE ciphertext of phase
+// Δ·m + e, with Δ = ⌊q/t⌋, see package rlwe. Ciphertexts can be added, and
+// multiplied by plaintexts or by ciphertexts. The product of two ciphertexts
+// is computed by tensoring over Z and scaling by t/q, and yields a ciphertext
+// of degree 2 that can be relinearized back to degree 1 with a relinearization
+// key. Plaintexts can be packed with negacyclic.BatchEncoder when t is a prime
+// satisfying t = 1 mod 2N.
+package bfv
+
+import (
+	"math/big"
+	"math/bits"
+
+	"negacyclic"
+	"negacyclic/rlwe"
+)
+
+// Parameters defines an instance of the scheme.
+type Parameters struct {
+	*rlwe.Parameters
+	T      *big.Int
+	Delta  *big.Int
+	tensor *negacyclic.CRTMultiplier
+}
+
+// Ciphertext is a tuple (c_0, ..., c_k) of elements of R_q, with phase
+// Σ c_i·s^i. Fresh ciphertexts have degree k = 1, and products of ciphertexts
+// have degree 2 until they are relinearized.
+type Ciphertext struct {
+	Value []*negacyclic.Polynomial
+}
+
+// RelinearizationKey is a key switching key from s^2 to s.
+type RelinearizationKey struct {
+	*rlwe.KeySwitchingKey
+}
+
+// NewParameters creates and returns the parameters of the scheme for ring
+// degree n, prime modulus q = 1 mod 2n, plaintext modulus t < q, and the given
+// secret and error distributions.
+func NewParameters(n int, q, t *big.Int, secret, err rlwe.Distribution) *Parameters {
+	if t.Cmp(big.NewInt(2)) < 0 || t.Cmp(q) >= 0 {
+		panic("bfv expects 2 <= t < q")
+	}
+	params := new(Parameters)
+	params.Parameters = rlwe.NewParameters(n, q, secret, err)
+	params.T = t
+	params.Delta = new(big.Int).Quo(q, t)
+
+	// The tensor product of two ciphertexts with coefficients in
+	// (-q/2, q/2] has coefficients bounded by N·q^2/2. Compute it exactly
+	// modulo the product of two NTT primes larger than N·q^2.
+	bitLen := q.BitLen() + (bits.Len(uint(n))+1)/2 + 1
+	p1 := negacyclic.RLWEPrime(bitLen, 2*n)
+	p2 := negacyclic.RLWEPrime(bitLen+1, 2*n)
+	params.tensor = negacyclic.NewCRTMultiplier(n, p1, p2)
+	return params
+}
+
+// Encrypt returns an encryption of the plaintext m in R_t, drawing its
+// randomness from src.
+func (params *Parameters) Encrypt(pk *rlwe.PublicKey, m *negacyclic.Polynomial, src negacyclic.Source) *Ciphertext {
+	pt := params.centerPlaintext(m)
+	pt.Scale(params.Delta)
+	ct := params.EncryptPolynomial(pk, pt, src)
+	return &Ciphertext{Value: []*negacyclic.Polynomial{ct.C0, ct.C1}}
+}
+
+// Decrypt returns the plaintext in R_t, with coefficients in [0, t),
+// encrypted by ct.
+func (params *Parameters) Decrypt(sk *rlwe.SecretKey, ct *Ciphertext) *negacyclic.Polynomial {
+	phase := params.phase(sk, ct)
+	phase.Scale(params.T)
+	m := phase.ScaleNearest(params.Q)
+	for _, coeff := range m.Coeffs {
+		coeff.Mod(coeff, params.T)
+	}
+	return m
+}
+
+// Add returns the homomorphic sum of x and y.
+func (params *Parameters) Add(x, y *Ciphertext) *Ciphertext {
+	return &Ciphertext{Value: rlwe.AddComponents(params.Q, x.Value, y.Value)}
+}
+
+// MulPlain returns the homomorphic product of ct with the plaintext m in R_t.
+func (params *Parameters) MulPlain(ct *Ciphertext, m *negacyclic.Polynomial) *Ciphertext {
+	pt := params.centerPlaintext(m)
+	prod := &Ciphertext{Value: make([]*negacyclic.Polynomial, len(ct.Value))}
+	for i, c := range ct.Value {
+		prod.Value[i] = params.Multiplier().Mul(c, pt)
+	}
+	return prod
+}
+
+// Mul returns the homomorphic product of two ciphertexts of degree 1, which is
+// a ciphertext of degree 2. It computes the tensor product
+// (c0·c0', c0·c1' + c1·c0', c1·c1') over Z and scales it by t/q with
+// rounding.
+func (params *Parameters) Mul(x, y *Ciphertext) *Ciphertext {
+	if len(x.Value) != 2 || len(y.Value) != 2 {
+		panic("bfv multiplies ciphertexts of degree 1 only")
+	}
+	x0, x1 := x.Value[0].Copy().Mod(params.Q), x.Value[1].Copy().Mod(params.Q)
+	y0, y1 := y.Value[0].Copy().Mod(params.Q), y.Value[1].Copy().Mod(params.Q)
+	tensor := []*negacyclic.Polynomial{
+		params.mulZ(x0, y0),
+		negacyclic.Add(params.mulZ(x0, y1), params.mulZ(x1, y0)),
+		params.mulZ(x1, y1),
+	}
+	prod := &Ciphertext{Value: make([]*negacyclic.Polynomial, len(tensor))}
+	for i, c := range tensor {
+		c.Scale(params.T)
+		prod.Value[i] = c.ScaleNearest(params.Q)
+		params.reduce(prod.Value[i])
+	}
+	return prod
+}
+
+// GenRelinearizationKey returns a relinearization key for the secret key sk,
+// with gadget base `base`, drawing its randomness from src.
+func (params *Parameters) GenRelinearizationKey(sk *rlwe.SecretKey, base *big.Int, src negacyclic.Source) *RelinearizationKey {
+	s := sk.S.Polynomial()
+	s2 := params.Multiplier().Mul(s, s)
+	return &RelinearizationKey{params.GenKeySwitchingKey(sk, s2, base, src)}
+}
+
+// Relinearize returns a ciphertext of degree 1 that encrypts the same
+// plaintext as the ciphertext of degree 2 ct.
+func (params *Parameters) Relinearize(ct *Ciphertext, rlk *RelinearizationKey) *Ciphertext {
+	return &Ciphertext{Value: rlk.Relinearize(params.Multiplier(), params.Q, ct.Value)}
+}
+
+// NoiseBudget returns the invariant noise budget of ct in bits, that is
+// -log2(2‖v‖∞) where t/q·(Σ c_i·s^i) = m + v + t·r. The ciphertext decrypts
+// correctly as long as its noise budget is positive.
+func (params *Parameters) NoiseBudget(sk *rlwe.SecretKey, ct *Ciphertext) int {
+	// q·v = t·x - q·m mod q·t, where x is the phase.
+	m := params.Decrypt(sk, ct)
+	w := params.phase(sk, ct)
+	w.Scale(params.T)
+	m.Scale(params.Q)
+	w = negacyclic.Sub(w, m)
+	qt := new(big.Int).Mul(params.Q, params.T)
+	norm := w.InfNorm(qt)
+	// log2(q) - log2(2‖q·v‖∞)
+	return rlwe.NoiseBudget(params.Q, norm)
+}
+
+// phase returns Σ c_i·s^i mod q, with coefficients in (-q/2, q/2].
+func (params *Parameters) phase(sk *rlwe.SecretKey, ct *Ciphertext) *negacyclic.Polynomial {
+	s := sk.S.Polynomial()
+	phase := ct.Value[len(ct.Value)-1].Copy()
+	for i := len(ct.Value) - 2; i >= 0; i-- {
+		// Horner's rule.
+		phase = params.Multiplier().Mul(phase, s)
+		phase = negacyclic.Add(phase, ct.Value[i])
+	}
+	return phase.Mod(params.Q)
+}
+
+// mulZ returns the product over Z of x and y, with coefficients in
+// (-q/2, q/2].
+func (params *Parameters) mulZ(x, y *negacyclic.Polynomial) *negacyclic.Polynomial {
+	return params.tensor.Mul(x, y).Mod(params.tensor.PQ)
+}
+
+// centerPlaintext returns the representative of m modulo t with coefficients
+// in (-t/2, t/2].
+func (params *Parameters) centerPlaintext(m *negacyclic.Polynomial) *negacyclic.Polynomial {
+	if m.Deg() != params.N {
+		panic("plaintext of unexpected degree")
+	}
+	return m.Copy().Mod(params.T)
+}
+
+func (params *Parameters) reduce(p *negacyclic.Polynomial) {
+	for _, coeff := range p.Coeffs {
+		coeff.Mod(coeff, params.Q)
+	}
+}
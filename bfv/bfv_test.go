@@ -0,0 +1,138 @@
+package bfv_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"negacyclic"
+	"negacyclic/bfv"
+	"negacyclic/rlwe"
+)
+
+func TestBFV(t *testing.T) {
+	t.Run("roundtrip", testRoundtrip)
+	t.Run("add", testAdd)
+	t.Run("mul_plain", testMulPlain)
+	t.Run("mul_relinearize", testMulRelinearize)
+	t.Run("noise_budget", testNoiseBudget)
+}
+
+func testParameters() (*bfv.Parameters, *negacyclic.BatchEncoder) {
+	n := 1 << 8
+	q := negacyclic.RLWEPrime(120, 2*n)
+	tMod := big.NewInt(65537)
+	params := bfv.NewParameters(n, q, tMod, rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 3.2})
+	return params, negacyclic.NewBatchEncoder(n, tMod)
+}
+
+func testRoundtrip(t *testing.T) {
+	params, _ := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	m := randomPlaintext(params)
+	checkEqual(t, m, params.Decrypt(sk, params.Encrypt(pk, m, negacyclic.DefaultSource)))
+}
+
+func testAdd(t *testing.T) {
+	params, enc := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	x, y := randomSlots(params), randomSlots(params)
+	ct := params.Add(params.Encrypt(pk, enc.Encode(x), negacyclic.DefaultSource), params.Encrypt(pk, enc.Encode(y), negacyclic.DefaultSource))
+	got := enc.Decode(params.Decrypt(sk, ct))
+	for i := range got {
+		if expected := (x[i] + y[i]) % params.T.Uint64(); got[i] != expected {
+			t.Fatalf("slot %d: expected %d, got %d", i, expected, got[i])
+		}
+	}
+}
+
+func testMulPlain(t *testing.T) {
+	params, enc := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	x, y := randomSlots(params), randomSlots(params)
+	ct := params.MulPlain(params.Encrypt(pk, enc.Encode(x), negacyclic.DefaultSource), enc.Encode(y))
+	checkSlotProduct(t, params, enc.Decode(params.Decrypt(sk, ct)), x, y)
+}
+
+func testMulRelinearize(t *testing.T) {
+	params, enc := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	rlk := params.GenRelinearizationKey(sk, big.NewInt(1<<20), negacyclic.DefaultSource)
+	x, y := randomSlots(params), randomSlots(params)
+	prod := params.Mul(params.Encrypt(pk, enc.Encode(x), negacyclic.DefaultSource), params.Encrypt(pk, enc.Encode(y), negacyclic.DefaultSource))
+	checkSlotProduct(t, params, enc.Decode(params.Decrypt(sk, prod)), x, y)
+	relin := params.Relinearize(prod, rlk)
+	if len(relin.Value) != 2 {
+		t.Fatalf("relinearized ciphertext of degree %d", len(relin.Value)-1)
+	}
+	checkSlotProduct(t, params, enc.Decode(params.Decrypt(sk, relin)), x, y)
+}
+
+func testNoiseBudget(t *testing.T) {
+	params, _ := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	rlk := params.GenRelinearizationKey(sk, big.NewInt(1<<20), negacyclic.DefaultSource)
+	m := randomPlaintext(params)
+	ct := params.Encrypt(pk, m, negacyclic.DefaultSource)
+	budget := params.NoiseBudget(sk, ct)
+	t.Logf("fresh ciphertext: %d bits", budget)
+	if budget <= 0 || budget >= params.Delta.BitLen() {
+		t.Fatalf("fresh noise budget of %d bits", budget)
+	}
+	// Square until the noise budget is exhausted.
+	mulT := negacyclic.NewMultiplier(params.N, params.T)
+	expected := m
+	for depth := 1; ; depth++ {
+		ct = params.Relinearize(params.Mul(ct, ct), rlk)
+		expected = mulT.Mul(expected, expected)
+		newBudget := params.NoiseBudget(sk, ct)
+		t.Logf("depth %d: %d bits", depth, newBudget)
+		if newBudget >= budget {
+			t.Fatalf("noise budget increased from %d to %d bits", budget, newBudget)
+		}
+		budget = newBudget
+		if budget == 0 {
+			if depth < 2 {
+				t.Fatal("noise budget exhausted too early")
+			}
+			return
+		}
+		checkEqual(t, expected, params.Decrypt(sk, ct))
+	}
+}
+
+func randomPlaintext(params *bfv.Parameters) *negacyclic.Polynomial {
+	m := negacyclic.NewPolynomial(params.N)
+	for _, coeff := range m.Coeffs {
+		coeff.Rand(rand.New(rand.NewSource(rand.Int63())), params.T)
+	}
+	return m
+}
+
+func randomSlots(params *bfv.Parameters) []uint64 {
+	values := make([]uint64, params.N)
+	for i := range values {
+		values[i] = rand.Uint64() % params.T.Uint64()
+	}
+	return values
+}
+
+func checkEqual(t *testing.T, expected, got *negacyclic.Polynomial) {
+	t.Helper()
+	for i := range expected.Coeffs {
+		if expected.Coeffs[i].Cmp(got.Coeffs[i]) != 0 {
+			t.Fatalf("coefficient %d: expected %d, got %d", i, expected.Coeffs[i], got.Coeffs[i])
+		}
+	}
+}
+
+func checkSlotProduct(t *testing.T, params *bfv.Parameters, got, x, y []uint64) {
+	t.Helper()
+	for i := range got {
+		expected := new(big.Int).SetUint64(x[i])
+		expected.Mul(expected, new(big.Int).SetUint64(y[i])).Mod(expected, params.T)
+		if got[i] != expected.Uint64() {
+			t.Fatalf("slot %d: expected %d, got %d", i, expected, got[i])
+		}
+	}
+}
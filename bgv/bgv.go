@@ -0,0 +1,266 @@
+// Package bgv implements the leveled homomorphic encryption scheme of
+// Brakerski, Gentry and Vaikuntanathan (BGV) over the negacyclic ring
+// R_Q = Z_Q[X]/(X^N+1), for a plaintext modulus t and a chain of primes
+// Q_L = q_0 q_1 ... q_L.
+//
+// A plaintext m in R_t sits in the low bits of the phase of a ciphertext:
+// c0 + c1·s = m + t·e mod Q_l, where l is the level of the ciphertext.
+// Ciphertexts can be added, and multiplied by plaintexts or by ciphertexts.
+// After each multiplication, the noise is reduced by modulus switching from
+// Q_l to Q_{l-1}, which divides the ciphertext by q_l with a rounding that
+// preserves its value modulo t. This requires q_i = 1 mod t, see GenModuli.
+package bgv
+
+import (
+	"math/big"
+
+	"negacyclic"
+	"negacyclic/rlwe"
+)
+
+// Parameters defines an instance of the scheme.
+type Parameters struct {
+	N      int
+	T      *big.Int
+	Moduli []*big.Int
+	Secret rlwe.Distribution
+	Error  rlwe.Distribution
+	ring   *negacyclic.RNSMultiplier
+}
+
+// Ciphertext is a tuple (c_0, ..., c_k) of elements of R_{Q_l}, with phase
+// Σ c_i·s^i, at level l. Fresh ciphertexts have degree k = 1 and level L, and
+// products of ciphertexts have degree 2 until they are relinearized.
+type Ciphertext struct {
+	Value []*negacyclic.Polynomial
+	Level int
+}
+
+// RelinearizationKey is a key switching key from s^2 to s: it consists of the
+// encryptions modulo Q_L of B^i·s^2, for the gadget base B and
+// i = 0, ..., l-1, with B^l >= Q_L.
+type RelinearizationKey struct {
+	*rlwe.KeySwitchingKey
+}
+
+// GenModuli returns a chain of `count` distinct primes of given bit length,
+// satisfying q = 1 mod 2n and q = 1 mod t, see negacyclic.RLWEPrimeChain.
+func GenModuli(n int, t *big.Int, bitLen, count int) []*big.Int {
+	twoN := big.NewInt(int64(2 * n))
+	gcd := new(big.Int).GCD(nil, nil, twoN, t)
+	lcm := new(big.Int).Mul(twoN, t)
+	lcm.Quo(lcm, gcd)
+	if !lcm.IsInt64() {
+		panic("plaintext modulus too large")
+	}
+	return negacyclic.RLWEPrimeChain(bitLen, int(lcm.Int64()), count)
+}
+
+// NewParameters creates and returns the parameters of the scheme for ring
+// degree n, plaintext modulus t, chain of primes q_0, ..., q_L satisfying
+// q_i = 1 mod 2n and q_i = 1 mod t, and the given secret and error
+// distributions.
+func NewParameters(n int, t *big.Int, moduli []*big.Int, secret, err rlwe.Distribution) *Parameters {
+	if t.Cmp(big.NewInt(2)) < 0 {
+		panic("bgv expects t >= 2")
+	}
+	one := big.NewInt(1)
+	for _, q := range moduli {
+		if new(big.Int).Mod(q, t).Cmp(one) != 0 {
+			panic("bgv expects moduli q = 1 mod t")
+		}
+	}
+	params := new(Parameters)
+	params.N = n
+	params.T = t
+	params.Moduli = moduli
+	params.Secret = secret
+	params.Error = err
+	params.ring = negacyclic.NewRNSMultiplier(n, moduli)
+	return params
+}
+
+// MaxLevel returns the level L of fresh ciphertexts.
+func (params *Parameters) MaxLevel() int {
+	return len(params.Moduli) - 1
+}
+
+// Modulus returns Q_l = q_0 ... q_l.
+func (params *Parameters) Modulus(level int) *big.Int {
+	return params.ring.AtLevel(level).Mod
+}
+
+// KeyGen samples and returns a key pair. The public key is (b, a) with
+// b = -a·s + t·e mod Q_L, drawing its randomness from src.
+func (params *Parameters) KeyGen(src negacyclic.Source) (*rlwe.SecretKey, *rlwe.PublicKey) {
+	sk := &rlwe.SecretKey{S: params.Secret.Sample(params.N, src)}
+	ct := params.encryptSymmetric(sk, negacyclic.NewPolynomial(params.N), src)
+	return sk, &rlwe.PublicKey{B: ct.C0, A: ct.C1}
+}
+
+// Encrypt returns an encryption at level L of the plaintext m in R_t, that is,
+// (b·u + t·e1 + m, a·u + t·e2), where u, e1 and e2 are drawn from src.
+func (params *Parameters) Encrypt(pk *rlwe.PublicKey, m *negacyclic.Polynomial, src negacyclic.Source) *Ciphertext {
+	pt := params.centerPlaintext(m)
+	u := params.Secret.Sample(params.N, src).Polynomial()
+	c0 := negacyclic.Add(params.ring.Mul(pk.B, u), params.noise(src))
+	c0 = negacyclic.Add(c0, pt)
+	c1 := negacyclic.Add(params.ring.Mul(pk.A, u), params.noise(src))
+	ct := &Ciphertext{Value: []*negacyclic.Polynomial{c0, c1}, Level: params.MaxLevel()}
+	params.reduce(ct)
+	return ct
+}
+
+// Decrypt returns the plaintext in R_t, with coefficients in [0, t),
+// encrypted by ct.
+func (params *Parameters) Decrypt(sk *rlwe.SecretKey, ct *Ciphertext) *negacyclic.Polynomial {
+	m := params.phase(sk, ct)
+	for _, coeff := range m.Coeffs {
+		coeff.Mod(coeff, params.T)
+	}
+	return m
+}
+
+// Add returns the homomorphic sum of x and y, which must be at the same level.
+func (params *Parameters) Add(x, y *Ciphertext) *Ciphertext {
+	if x.Level != y.Level {
+		panic("addition of ciphertexts at different levels, see ModSwitchTo")
+	}
+	q := params.Modulus(x.Level)
+	return &Ciphertext{Value: rlwe.AddComponents(q, x.Value, y.Value), Level: x.Level}
+}
+
+// MulPlain returns the homomorphic product of ct with the plaintext m in R_t.
+func (params *Parameters) MulPlain(ct *Ciphertext, m *negacyclic.Polynomial) *Ciphertext {
+	pt := params.centerPlaintext(m)
+	ring := params.ring.AtLevel(ct.Level)
+	prod := &Ciphertext{Value: make([]*negacyclic.Polynomial, len(ct.Value)), Level: ct.Level}
+	for i, c := range ct.Value {
+		prod.Value[i] = ring.Mul(c, pt)
+	}
+	return prod
+}
+
+// Mul returns the homomorphic product of two ciphertexts of degree 1 at the
+// same level, which is the ciphertext (c0·c0', c0·c1' + c1·c0', c1·c1') of
+// degree 2.
+func (params *Parameters) Mul(x, y *Ciphertext) *Ciphertext {
+	if len(x.Value) != 2 || len(y.Value) != 2 {
+		panic("bgv multiplies ciphertexts of degree 1 only")
+	}
+	if x.Level != y.Level {
+		panic("product of ciphertexts at different levels, see ModSwitchTo")
+	}
+	ring := params.ring.AtLevel(x.Level)
+	return &Ciphertext{Value: rlwe.Tensor(ring, ring.Mod, x.Value, y.Value), Level: x.Level}
+}
+
+// GenRelinearizationKey returns a relinearization key for the secret key sk,
+// with gadget base `base`, drawing its randomness from src.
+func (params *Parameters) GenRelinearizationKey(sk *rlwe.SecretKey, base *big.Int, src negacyclic.Source) *RelinearizationKey {
+	s := sk.S.Polynomial()
+	s2 := params.ring.Mul(s, s)
+	ksk := rlwe.NewKeySwitchingKey(s2, base, params.Modulus(params.MaxLevel()), func(pt *negacyclic.Polynomial) *rlwe.Ciphertext {
+		return params.encryptSymmetric(sk, pt, src)
+	})
+	return &RelinearizationKey{ksk}
+}
+
+// Relinearize returns a ciphertext of degree 1 that encrypts the same
+// plaintext as the ciphertext of degree 2 ct. Its noise grows by
+// t·Σ d_i·e_i, where the d_i are the signed digits of c_2 in base B.
+func (params *Parameters) Relinearize(ct *Ciphertext, rlk *RelinearizationKey) *Ciphertext {
+	ring := params.ring.AtLevel(ct.Level)
+	return &Ciphertext{Value: rlk.Relinearize(ring, ring.Mod, ct.Value), Level: ct.Level}
+}
+
+// ModSwitch returns a ciphertext at level l-1 that encrypts the same plaintext
+// as the ciphertext ct at level l. Each component c is mapped to
+// (c - δ)/q_l, where δ = c mod q_l, δ = 0 mod t and ‖δ‖∞ <= t·q_l/2, so that
+// the noise is divided by q_l and increased by at most t/2·(1 + ‖s‖_1).
+func (params *Parameters) ModSwitch(ct *Ciphertext) *Ciphertext {
+	if ct.Level == 0 {
+		panic("cannot switch below level 0")
+	}
+	q := params.Moduli[ct.Level]
+	tInv := new(big.Int).ModInverse(params.T, q)
+	half := new(big.Int).Rsh(q, 1)
+	switched := &Ciphertext{Value: make([]*negacyclic.Polynomial, len(ct.Value)), Level: ct.Level - 1}
+	delta := new(big.Int)
+	for i, c := range ct.Value {
+		switched.Value[i] = negacyclic.NewPolynomial(params.N)
+		for j, coeff := range c.Coeffs {
+			// δ = t·[c·t^{-1}]_q, with [.]_q in (-q/2, q/2].
+			delta.Mul(coeff, tInv).Mod(delta, q)
+			if delta.Cmp(half) > 0 {
+				delta.Sub(delta, q)
+			}
+			delta.Mul(delta, params.T)
+			switched.Value[i].Coeffs[j].Sub(coeff, delta).Quo(switched.Value[i].Coeffs[j], q)
+		}
+	}
+	params.reduce(switched)
+	return switched
+}
+
+// ModSwitchTo repeatedly switches the modulus of ct down to the given level.
+func (params *Parameters) ModSwitchTo(ct *Ciphertext, level int) *Ciphertext {
+	if level > ct.Level {
+		panic("cannot switch to a higher level")
+	}
+	for ct.Level > level {
+		ct = params.ModSwitch(ct)
+	}
+	return ct
+}
+
+// NoiseBudget returns the noise budget of ct in bits, that is
+// log2(Q_l/2) - log2‖m + t·e‖∞, where m + t·e is the phase of ct. The
+// ciphertext decrypts correctly as long as its noise budget is positive.
+func (params *Parameters) NoiseBudget(sk *rlwe.SecretKey, ct *Ciphertext) int {
+	norm := params.phase(sk, ct).InfNorm(nil)
+	return rlwe.NoiseBudget(params.Modulus(ct.Level), norm)
+}
+
+// phase returns Σ c_i·s^i mod Q_l, with coefficients in (-Q_l/2, Q_l/2].
+func (params *Parameters) phase(sk *rlwe.SecretKey, ct *Ciphertext) *negacyclic.Polynomial {
+	ring := params.ring.AtLevel(ct.Level)
+	s := sk.S.Polynomial()
+	phase := ct.Value[len(ct.Value)-1].Copy()
+	for i := len(ct.Value) - 2; i >= 0; i-- {
+		// Horner's rule.
+		phase = negacyclic.Add(ring.Mul(phase, s), ct.Value[i])
+	}
+	return phase.Mod(ring.Mod)
+}
+
+// encryptSymmetric returns the encryption (-a·s + t·e + pt, a) modulo Q_L of
+// the plaintext polynomial pt, where a and e are drawn from src.
+func (params *Parameters) encryptSymmetric(sk *rlwe.SecretKey, pt *negacyclic.Polynomial, src negacyclic.Source) *rlwe.Ciphertext {
+	return rlwe.EncryptSymmetricMod(params.ring, params.Modulus(params.MaxLevel()), sk, pt, params.noise(src), src)
+}
+
+// noise returns t·e for e drawn from the error distribution and src.
+func (params *Parameters) noise(src negacyclic.Source) *negacyclic.Polynomial {
+	e := params.Error.Sample(params.N, src).Polynomial()
+	e.Scale(params.T)
+	return e
+}
+
+// centerPlaintext returns the representative of m modulo t with coefficients
+// in (-t/2, t/2].
+func (params *Parameters) centerPlaintext(m *negacyclic.Polynomial) *negacyclic.Polynomial {
+	if m.Deg() != params.N {
+		panic("plaintext of unexpected degree")
+	}
+	return m.Copy().Mod(params.T)
+}
+
+func (params *Parameters) reduce(ct *Ciphertext) {
+	q := params.Modulus(ct.Level)
+	for _, c := range ct.Value {
+		for _, coeff := range c.Coeffs {
+			coeff.Mod(coeff, q)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package bgv_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"negacyclic"
+	"negacyclic/bgv"
+	"negacyclic/rlwe"
+)
+
+func TestBGV(t *testing.T) {
+	t.Run("roundtrip", testRoundtrip)
+	t.Run("add", testAdd)
+	t.Run("mul_plain", testMulPlain)
+	t.Run("mod_switch", testModSwitch)
+	t.Run("circuit", testCircuit)
+}
+
+func testParameters() (*bgv.Parameters, *negacyclic.BatchEncoder) {
+	n := 1 << 8
+	tMod := big.NewInt(65537)
+	moduli := bgv.GenModuli(n, tMod, 60, 4)
+	params := bgv.NewParameters(n, tMod, moduli, rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 3.2})
+	return params, negacyclic.NewBatchEncoder(n, tMod)
+}
+
+func testRoundtrip(t *testing.T) {
+	params, _ := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	m := randomPlaintext(params)
+	checkEqual(t, m, params.Decrypt(sk, params.Encrypt(pk, m, negacyclic.DefaultSource)))
+}
+
+func testAdd(t *testing.T) {
+	params, enc := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	x, y := randomSlots(params), randomSlots(params)
+	ct := params.Add(params.Encrypt(pk, enc.Encode(x), negacyclic.DefaultSource), params.Encrypt(pk, enc.Encode(y), negacyclic.DefaultSource))
+	got := enc.Decode(params.Decrypt(sk, ct))
+	for i := range got {
+		if expected := (x[i] + y[i]) % params.T.Uint64(); got[i] != expected {
+			t.Fatalf("slot %d: expected %d, got %d", i, expected, got[i])
+		}
+	}
+}
+
+func testMulPlain(t *testing.T) {
+	params, enc := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	x, y := randomSlots(params), randomSlots(params)
+	ct := params.MulPlain(params.Encrypt(pk, enc.Encode(x), negacyclic.DefaultSource), enc.Encode(y))
+	checkSlotProduct(t, params, enc.Decode(params.Decrypt(sk, ct)), x, y)
+}
+
+func testModSwitch(t *testing.T) {
+	params, _ := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	m := randomPlaintext(params)
+	ct := params.Encrypt(pk, m, negacyclic.DefaultSource)
+	for ct.Level > 0 {
+		ct = params.ModSwitch(ct)
+		checkEqual(t, m, params.Decrypt(sk, ct))
+	}
+	for _, coeff := range ct.Value[0].Coeffs {
+		if coeff.Sign() < 0 || coeff.Cmp(params.Moduli[0]) >= 0 {
+			t.Fatal("ciphertext not reduced modulo q_0")
+		}
+	}
+	if budget := params.NoiseBudget(sk, ct); budget <= 0 {
+		t.Fatalf("noise budget of %d bits at level 0", budget)
+	}
+}
+
+// testCircuit squares a ciphertext repeatedly, switching the modulus after each
+// multiplication.
+func testCircuit(t *testing.T) {
+	params, enc := testParameters()
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	rlk := params.GenRelinearizationKey(sk, big.NewInt(1<<20), negacyclic.DefaultSource)
+	x := randomSlots(params)
+	ct := params.Encrypt(pk, enc.Encode(x), negacyclic.DefaultSource)
+	t.Logf("level %d: %d bits", ct.Level, params.NoiseBudget(sk, ct))
+	for ct.Level > 0 {
+		prod := params.Relinearize(params.Mul(ct, ct), rlk)
+		before := params.NoiseBudget(sk, prod)
+		ct = params.ModSwitch(prod)
+		after := params.NoiseBudget(sk, ct)
+		t.Logf("level %d: %d bits, %d bits after switching", ct.Level, before, after)
+		if after <= 0 {
+			t.Fatalf("noise budget exhausted at level %d", ct.Level)
+		}
+		checkSlotProduct(t, params, enc.Decode(params.Decrypt(sk, ct)), x, x)
+		x = enc.Decode(params.Decrypt(sk, ct))
+	}
+}
+
+func randomPlaintext(params *bgv.Parameters) *negacyclic.Polynomial {
+	m := negacyclic.NewPolynomial(params.N)
+	for _, coeff := range m.Coeffs {
+		coeff.SetInt64(rand.Int63n(params.T.Int64()))
+	}
+	return m
+}
+
+func randomSlots(params *bgv.Parameters) []uint64 {
+	values := make([]uint64, params.N)
+	for i := range values {
+		values[i] = rand.Uint64() % params.T.Uint64()
+	}
+	return values
+}
+
+func checkEqual(t *testing.T, expected, got *negacyclic.Polynomial) {
+	t.Helper()
+	for i := range expected.Coeffs {
+		if expected.Coeffs[i].Cmp(got.Coeffs[i]) != 0 {
+			t.Fatalf("coefficient %d: expected %d, got %d", i, expected.Coeffs[i], got.Coeffs[i])
+		}
+	}
+}
+
+func checkSlotProduct(t *testing.T, params *bgv.Parameters, got, x, y []uint64) {
+	t.Helper()
+	for i := range got {
+		expected := new(big.Int).SetUint64(x[i])
+		expected.Mul(expected, new(big.Int).SetUint64(y[i])).Mod(expected, params.T)
+		if got[i] != expected.Uint64() {
+			t.Fatalf("slot %d: expected %d, got %d", i, expected, got[i])
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package negacyclic
+
+import (
+	"math"
+	"math/big"
+)
+
+const (
+	// bigGaussianBase is the largest σ sampled directly with a CDT table by
+	// BigGaussian, and the parameter σ_0 of its base sampler.
+	bigGaussianBase = 34
+	// bigGaussianSmoothing bounds the smoothing parameter η_ε(Z) of the
+	// integers, for ε about 2^-160.
+	bigGaussianSmoothing = 6
+	// bigGaussianRounding is the parameter σ̄ of the final randomized
+	// rounding of BigGaussian.
+	bigGaussianRounding = 24
+)
+
+// BigGaussian samples the discrete Gaussian D_{Z,σ} for large σ, such as the
+// σ ≥ 2^40 of noise flooding, with big.Int outputs. It follows Micciancio and
+// Walter, "Gaussian Sampling over the Integers: Efficient, Generic,
+// Constant-Time". Samples of D_{Z,σ_i} are combined as z_i·x + w_i·x' from
+// two samples of D_{Z,σ_{i-1}}, with z_i = ⌊σ_{i-1}/(√2·η)⌋ and
+// w_i = max(1, z_i - 1), so that σ_i² = (z_i² + w_i²)·σ_{i-1}², starting from
+// a GaussianCDT of parameter σ_0. Since σ_{i-1} >= √2·z_i·η for the smoothing
+// parameter η of Z, the combination is statistically close to D_{Z,σ_i}.
+// The stages grow σ doubly exponentially, and the last one is stretched to
+// σ by a real factor K, with a sample of D_{Z,σ̄,K·y} for y drawn from
+// D_{Z,σ_L}, which is close to D_{Z,σ} for σ² = K²σ_L² + σ̄² as long as
+// σ_L·σ̄ >= η·σ.
+type BigGaussian struct {
+	Sigma   float64
+	TailCut float64
+	base    *GaussianCDT
+	// z[i-1] and w[i-1] are the coefficients of stage i.
+	z, w     []*big.Int
+	k        *big.Float
+	rounding *CenteredGaussian
+}
+
+// NewBigGaussian returns the sampler of D_{Z,σ}, with base samplers truncated
+// at τ times their standard deviation.
+func NewBigGaussian(sigma, tailCut float64) *BigGaussian {
+	if sigma <= 0 || math.IsInf(sigma, 0) || math.IsNaN(sigma) {
+		panic("σ must be positive and finite")
+	}
+	if sigma <= bigGaussianBase {
+		return &BigGaussian{Sigma: sigma, TailCut: tailCut, base: NewGaussianCDT(sigma, tailCut)}
+	}
+	return newBigGaussian(big.NewFloat(sigma), tailCut)
+}
+
+// newBigGaussian returns the sampler of D_{Z,σ} for σ > bigGaussianBase, with
+// its stages computed at the precision of σ, and at least 128 bits.
+func newBigGaussian(sigma *big.Float, tailCut float64) *BigGaussian {
+	prec := sigma.Prec()
+	if prec < 128 {
+		prec = 128
+	}
+	g := &BigGaussian{TailCut: tailCut}
+	g.Sigma, _ = sigma.Float64()
+	g.base = NewGaussianCDT(bigGaussianBase, tailCut)
+	// The stages stop when σ_L·σ̄ >= η·σ.
+	target := new(big.Float).SetPrec(prec).Mul(sigma, big.NewFloat(bigGaussianSmoothing))
+	stage := new(big.Float).SetPrec(prec).SetInt64(bigGaussianBase)
+	divisor := new(big.Float).SetPrec(prec).SetInt64(2)
+	divisor.Sqrt(divisor).Mul(divisor, big.NewFloat(bigGaussianSmoothing))
+	product := new(big.Float).SetPrec(prec)
+	for product.Mul(stage, big.NewFloat(bigGaussianRounding)).Cmp(target) < 0 {
+		z, _ := new(big.Float).SetPrec(prec).Quo(stage, divisor).Int(nil)
+		w := new(big.Int).Sub(z, big.NewInt(1))
+		if w.Sign() <= 0 {
+			w.SetInt64(1)
+		}
+		g.z = append(g.z, z)
+		g.w = append(g.w, w)
+		norm := new(big.Int).Mul(z, z)
+		norm.Add(norm, new(big.Int).Mul(w, w))
+		factor := new(big.Float).SetPrec(prec).SetInt(norm)
+		stage.Mul(stage, factor.Sqrt(factor))
+	}
+	// K = sqrt(σ² - σ̄²)/σ_L.
+	g.k = new(big.Float).SetPrec(prec).Mul(sigma, sigma)
+	g.k.Sub(g.k, big.NewFloat(bigGaussianRounding*bigGaussianRounding))
+	g.k.Sqrt(g.k).Quo(g.k, stage)
+	g.rounding = NewCenteredGaussian(bigGaussianRounding, tailCut)
+	return g
+}
+
+// Bound returns the largest absolute value output by the sampler, a few times
+// τσ.
+func (g *BigGaussian) Bound() *big.Int {
+	bound := big.NewInt(int64(g.base.Bound()))
+	if g.k == nil {
+		return bound
+	}
+	for i := range g.z {
+		sum := new(big.Int).Add(g.z[i], g.w[i])
+		bound.Mul(bound, sum)
+	}
+	// |x - K·y| <= ⌈τ√2·σ̄⌉ + 1, see CenteredGaussian.
+	scaled := new(big.Float).SetPrec(uint(bound.BitLen()) + 64).SetInt(bound)
+	bound, _ = scaled.Mul(scaled, g.k).Int(nil)
+	return bound.Add(bound, big.NewInt(int64(g.rounding.proposal.Bound()+2)))
+}
+
+// Sample returns a sample of D_{Z,σ}, drawn from src.
+func (g *BigGaussian) Sample(src Source) *big.Int {
+	if g.k == nil {
+		return big.NewInt(int64(g.base.Sample(src)))
+	}
+	return g.sampleCentered(new(big.Float), src)
+}
+
+// sampleCentered returns a sample of D_{Z,σ,c}, the final randomized rounding
+// of center c + K·y instead of K·y, for g.k != nil.
+func (g *BigGaussian) sampleCentered(c *big.Float, src Source) *big.Int {
+	y := g.sampleStage(len(g.z), src)
+	prec := uint(y.BitLen()) + 64
+	if exp := c.MantExp(nil); exp > 0 && uint(exp)+64 > prec {
+		prec = uint(exp) + 64
+	}
+	center := new(big.Float).SetPrec(prec).SetInt(y)
+	center.Mul(center, g.k).Add(center, c)
+	return g.rounding.SampleBig(center, src)
+}
+
+// sampleStage returns a sample of D_{Z,σ_i}.
+func (g *BigGaussian) sampleStage(i int, src Source) *big.Int {
+	if i == 0 {
+		return big.NewInt(int64(g.base.Sample(src)))
+	}
+	x := g.sampleStage(i-1, src)
+	x.Mul(x, g.z[i-1])
+	y := g.sampleStage(i-1, src)
+	return x.Add(x, y.Mul(y, g.w[i-1]))
+}
+
+// SamplePolynomial returns a polynomial of given degree with independent
+// coefficients drawn from D_{Z,σ}.
+func (g *BigGaussian) SamplePolynomial(deg int, src Source) *Polynomial {
+	p := NewPolynomial(deg)
+	for i := range p.Coeffs {
+		p.Coeffs[i] = g.Sample(src)
+	}
+	return p
+}
+
+// DiscreteGaussianBig returns a polynomial of given degree with coefficients
+// drawn from D_{Z,σ} by a BigGaussian, for σ too large for DiscreteGaussian.
+func DiscreteGaussianBig(deg int, sigma, tailCut float64, src Source) *Polynomial {
+	return NewBigGaussian(sigma, tailCut).SamplePolynomial(deg, src)
+}
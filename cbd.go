@@ -0,0 +1,58 @@
+package negacyclic
+
+// CBD returns a vector of dim samples of the centered binomial distribution
+// CBD_η, drawn from src: each coefficient is Σ a_i - Σ b_i for 2η independent
+// uniform bits. The bits are read as a little-endian stream, η bits for the
+// a_i followed by η bits for the b_i, as in SamplePolyCBD of FIPS 203, so
+// that the coefficients lie in [-η, η] with variance η/2.
+func CBD(dim, eta int, src Source) *Vector {
+	if eta < 1 || eta > 16 {
+		panic("CBD expects 1 <= η <= 16")
+	}
+	buf := make([]byte, (2*eta*dim+7)/8)
+	readFull(src, buf)
+	v := NewVector(dim)
+	mask := uint64(1)<<eta - 1
+	// Words of 2η·k bits hold the bits of k coefficients, in 2k lanes of η
+	// bits alternately holding the a_i and the b_i. As in the reference
+	// implementation of Kyber, the lanes are summed in parallel by adding
+	// the η shifts of a word masked to the low bit of each lane: the sums
+	// are at most η and do not carry into the next lane.
+	k := 56 / (2 * eta)
+	var lanes uint64
+	for l := 0; l < 2*k; l++ {
+		lanes |= 1 << (l * eta)
+	}
+	// acc buffers the next unread bits of buf, least significant first.
+	var acc uint64
+	accBits, next := 0, 0
+	for i := 0; i < dim; i += k {
+		m := k
+		if dim-i < m {
+			m = dim - i
+		}
+		for accBits < 2*eta*m {
+			acc |= uint64(buf[next]) << accBits
+			accBits += 8
+			next++
+		}
+		var sum uint64
+		for j := 0; j < eta; j++ {
+			sum += acc >> j & lanes
+		}
+		for c := 0; c < m; c++ {
+			a := sum >> (2 * c * eta) & mask
+			b := sum >> ((2*c + 1) * eta) & mask
+			v.Coeffs[i+c] = int(a) - int(b)
+		}
+		acc >>= 2 * eta * m
+		accBits -= 2 * eta * m
+	}
+	return v
+}
+
+// CBDFromSeed is CBD drawn from NewSeededSource(seed, nonce), a PRF of the
+// 32-byte seed and the nonce: the same inputs always give the same output.
+func CBDFromSeed(dim, eta int, seed, nonce []byte) *Vector {
+	return CBD(dim, eta, NewSeededSource(seed, nonce))
+}
@@ -0,0 +1,133 @@
+package negacyclic
+
+import (
+	"math"
+	"math/big"
+)
+
+// CenteredGaussian samples the discrete Gaussian D_{Z,σ,c} of probability
+// proportional to exp(-(x-c)²/(2σ²)) over the integers, for any real center c
+// chosen per sample. For σ up to 34, it proposes ⌊c⌋ + y for y drawn from a
+// GaussianCDT of parameter √2·σ, and accepts with probability proportional to
+// the ratio of the target and proposal weights, about 0.7 on average. The
+// distribution is then truncated to about τ√2·σ around the center. For larger
+// σ, it shifts the center of the final randomized rounding of BigGaussian by
+// c. Unlike GaussianCDT, the running time depends on the output.
+type CenteredGaussian struct {
+	Sigma    *big.Float
+	TailCut  float64
+	proposal *GaussianCDT
+	// sigma is Sigma as a float64, for the rejection from proposal.
+	sigma float64
+	// stages samples D_{Z,σ,c} for σ beyond bigGaussianBase.
+	stages *BigGaussian
+}
+
+// NewCenteredGaussian returns the sampler of D_{Z,σ,c}, with tail cut τ.
+func NewCenteredGaussian(sigma, tailCut float64) *CenteredGaussian {
+	if math.IsInf(sigma, 0) || math.IsNaN(sigma) {
+		panic("σ must be positive and finite")
+	}
+	return NewCenteredGaussianBig(big.NewFloat(sigma), tailCut)
+}
+
+// NewCenteredGaussianBig is NewCenteredGaussian for an arbitrary-precision σ,
+// such as the σ ≥ 2^40 of noise flooding.
+func NewCenteredGaussianBig(sigma *big.Float, tailCut float64) *CenteredGaussian {
+	if sigma.Sign() <= 0 || sigma.IsInf() {
+		panic("σ must be positive and finite")
+	}
+	g := &CenteredGaussian{Sigma: new(big.Float).Set(sigma), TailCut: tailCut}
+	if sigma.Cmp(big.NewFloat(bigGaussianBase)) > 0 {
+		g.stages = newBigGaussian(sigma, tailCut)
+		return g
+	}
+	g.sigma, _ = sigma.Float64()
+	g.proposal = NewGaussianCDT(math.Sqrt2*g.sigma, tailCut)
+	return g
+}
+
+// Sample returns a sample of D_{Z,σ,c}, drawn from src. The center and σ must
+// be small enough for the output to fit in an int.
+func (g *CenteredGaussian) Sample(c float64, src Source) int {
+	if g.stages != nil {
+		x := g.SampleBig(big.NewFloat(c), src)
+		if !x.IsInt64() || int64(int(x.Int64())) != x.Int64() {
+			panic("sample does not fit in an int")
+		}
+		return int(x.Int64())
+	}
+	floor := math.Floor(c)
+	return int(floor) + g.sampleOffset(c-floor, src)
+}
+
+// SampleBig is Sample for an arbitrary-precision center.
+func (g *CenteredGaussian) SampleBig(c *big.Float, src Source) *big.Int {
+	if g.stages != nil {
+		return g.stages.sampleCentered(c, src)
+	}
+	floor, acc := c.Int(nil)
+	if acc == big.Above {
+		// Int truncates towards zero, above c for negative non-integers.
+		floor.Sub(floor, big.NewInt(1))
+	}
+	frac, _ := new(big.Float).Sub(c, new(big.Float).SetInt(floor)).Float64()
+	return floor.Add(floor, big.NewInt(int64(g.sampleOffset(frac, src))))
+}
+
+// SampleVector returns a vector whose coefficient i is drawn from
+// D_{Z,σ,centers[i]}.
+func (g *CenteredGaussian) SampleVector(centers []float64, src Source) *Vector {
+	v := NewVector(len(centers))
+	for i, c := range centers {
+		v.Coeffs[i] = g.Sample(c, src)
+	}
+	return v
+}
+
+// SamplePolynomial returns a polynomial whose coefficient i is drawn from
+// D_{Z,σ,centers[i]}.
+func (g *CenteredGaussian) SamplePolynomial(centers []*big.Float, src Source) *Polynomial {
+	p := NewPolynomial(len(centers))
+	for i, c := range centers {
+		p.Coeffs[i] = g.SampleBig(c, src)
+	}
+	return p
+}
+
+// SampleCoset returns a vector x with x_i = coset_i mod q, drawn from the
+// discrete Gaussian over the coset coset_i + qZ of parameter q·σ and center
+// centers[i]. It samples y from D_{Z,σ,(c_i - coset_i)/q} and returns
+// coset_i + q·y, as needed for instance by the gadget lattice samplers of
+// trapdoor constructions.
+func (g *CenteredGaussian) SampleCoset(coset *Vector, q int, centers []float64, src Source) *Vector {
+	if coset.Len() != len(centers) {
+		panic("coset and centers of different lengths")
+	}
+	if q < 1 {
+		panic("coset modulus must be positive")
+	}
+	v := NewVector(coset.Len())
+	for i, a := range coset.Coeffs {
+		v.Coeffs[i] = a + q*g.Sample((centers[i]-float64(a))/float64(q), src)
+	}
+	return v
+}
+
+// sampleOffset returns y such that ⌊c⌋ + y is drawn from D_{Z,σ,c}, for the
+// fractional part f of c.
+func (g *CenteredGaussian) sampleOffset(f float64, src Source) int {
+	// The log-ratio of the target and proposal weights is
+	// h(y) = -(y-f)²/(2σ²) + y²/(4σ²), maximal at y = 2f, where it is
+	// f²/(2σ²).
+	s2 := g.sigma * g.sigma
+	hMax := f * f / (2 * s2)
+	for {
+		y := g.proposal.Sample(src)
+		d := float64(y) - f
+		h := -d*d/(2*s2) + float64(y*y)/(4*s2)
+		if randFloat64(src) < math.Exp(h-hMax) {
+			return y
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package ckks
+
+import (
+	"math"
+	"math/big"
+
+	"negacyclic"
+	"negacyclic/rlwe"
+)
+
+// Parameters defines an instance of the scheme, over the ring R_Q for a chain
+// of primes Q_L = q_0 q_1 ... q_L. The primes q_1, ..., q_L are consumed by
+// rescaling, and are typically close to the scale Δ.
+type Parameters struct {
+	N      int
+	Moduli []*big.Int
+	Secret rlwe.Distribution
+	Error  rlwe.Distribution
+	ring   *negacyclic.RNSMultiplier
+}
+
+// Ciphertext is a tuple (c_0, ..., c_k) of elements of R_{Q_l}, with phase
+// Σ c_i·s^i, at level l. Its phase is Scale times the encoded values, up to
+// the noise. Fresh ciphertexts have degree k = 1 and level L, and products of
+// ciphertexts have degree 2 until they are relinearized.
+type Ciphertext struct {
+	Value []*negacyclic.Polynomial
+	Scale float64
+	Level int
+}
+
+// RelinearizationKey is a key switching key from s^2 to s: it consists of the
+// encryptions modulo Q_L of B^i·s^2, for the gadget base B and
+// i = 0, ..., l-1, with B^l >= Q_L.
+type RelinearizationKey struct {
+	*rlwe.KeySwitchingKey
+}
+
+// NewParameters creates and returns the parameters of the scheme for ring
+// degree n, chain of distinct primes q_0, ..., q_L satisfying q_i = 1 mod 2n,
+// and the given secret and error distributions.
+func NewParameters(n int, moduli []*big.Int, secret, err rlwe.Distribution) *Parameters {
+	params := new(Parameters)
+	params.N = n
+	params.Moduli = moduli
+	params.Secret = secret
+	params.Error = err
+	params.ring = negacyclic.NewRNSMultiplier(n, moduli)
+	return params
+}
+
+// MaxLevel returns the level L of fresh ciphertexts.
+func (params *Parameters) MaxLevel() int {
+	return len(params.Moduli) - 1
+}
+
+// Modulus returns Q_l = q_0 ... q_l.
+func (params *Parameters) Modulus(level int) *big.Int {
+	return params.ring.AtLevel(level).Mod
+}
+
+// KeyGen samples and returns a key pair. The public key is (b, a) with
+// b = -a·s + e mod Q_L, drawing its randomness from src.
+func (params *Parameters) KeyGen(src negacyclic.Source) (*rlwe.SecretKey, *rlwe.PublicKey) {
+	sk := &rlwe.SecretKey{S: params.Secret.Sample(params.N, src)}
+	ct := params.encryptSymmetric(sk, negacyclic.NewPolynomial(params.N), src)
+	return sk, &rlwe.PublicKey{B: ct.C0, A: ct.C1}
+}
+
+// Encrypt returns an encryption at level L of the plaintext pt, that is,
+// (b·u + e1 + pt, a·u + e2), where u, e1 and e2 are drawn from src.
+func (params *Parameters) Encrypt(pk *rlwe.PublicKey, pt *Plaintext, src negacyclic.Source) *Ciphertext {
+	if pt.Value.Deg() != params.N {
+		panic("plaintext of unexpected degree")
+	}
+	u := params.Secret.Sample(params.N, src).Polynomial()
+	c0 := negacyclic.Add(params.ring.Mul(pk.B, u), params.Error.Sample(params.N, src))
+	c0 = negacyclic.Add(c0, pt.Value)
+	c1 := negacyclic.Add(params.ring.Mul(pk.A, u), params.Error.Sample(params.N, src))
+	ct := &Ciphertext{
+		Value: []*negacyclic.Polynomial{c0, c1},
+		Scale: pt.Scale,
+		Level: params.MaxLevel(),
+	}
+	params.reduce(ct)
+	return ct
+}
+
+// Decrypt returns the plaintext encrypted by ct, that is, its phase with
+// coefficients in (-Q_l/2, Q_l/2], which can be decoded with Encoder.Decode.
+func (params *Parameters) Decrypt(sk *rlwe.SecretKey, ct *Ciphertext) *Plaintext {
+	ring := params.ring.AtLevel(ct.Level)
+	s := sk.S.Polynomial()
+	phase := ct.Value[len(ct.Value)-1].Copy()
+	for i := len(ct.Value) - 2; i >= 0; i-- {
+		// Horner's rule.
+		phase = negacyclic.Add(ring.Mul(phase, s), ct.Value[i])
+	}
+	return &Plaintext{Value: phase.Mod(ring.Mod), Scale: ct.Scale}
+}
+
+// Add returns the homomorphic sum of x and y, which must be at the same level
+// and scale.
+func (params *Parameters) Add(x, y *Ciphertext) *Ciphertext {
+	if x.Level != y.Level {
+		panic("addition of ciphertexts at different levels, see DropLevel")
+	}
+	if math.Abs(x.Scale-y.Scale) > 1e-9*x.Scale {
+		panic("addition of ciphertexts at different scales")
+	}
+	q := params.Modulus(x.Level)
+	return &Ciphertext{Value: rlwe.AddComponents(q, x.Value, y.Value), Scale: x.Scale, Level: x.Level}
+}
+
+// MulPlain returns the homomorphic product of ct with the plaintext pt, at
+// scale the product of their scales.
+func (params *Parameters) MulPlain(ct *Ciphertext, pt *Plaintext) *Ciphertext {
+	ring := params.ring.AtLevel(ct.Level)
+	prod := &Ciphertext{
+		Value: make([]*negacyclic.Polynomial, len(ct.Value)),
+		Scale: ct.Scale * pt.Scale,
+		Level: ct.Level,
+	}
+	for i, c := range ct.Value {
+		prod.Value[i] = ring.Mul(c, pt.Value)
+	}
+	return prod
+}
+
+// Mul returns the homomorphic product of two ciphertexts of degree 1 at the
+// same level, which is the ciphertext (c0·c0', c0·c1' + c1·c0', c1·c1') of
+// degree 2, at scale the product of their scales.
+func (params *Parameters) Mul(x, y *Ciphertext) *Ciphertext {
+	if len(x.Value) != 2 || len(y.Value) != 2 {
+		panic("ckks multiplies ciphertexts of degree 1 only")
+	}
+	if x.Level != y.Level {
+		panic("product of ciphertexts at different levels, see DropLevel")
+	}
+	ring := params.ring.AtLevel(x.Level)
+	return &Ciphertext{
+		Value: rlwe.Tensor(ring, ring.Mod, x.Value, y.Value),
+		Scale: x.Scale * y.Scale,
+		Level: x.Level,
+	}
+}
+
+// GenRelinearizationKey returns a relinearization key for the secret key sk,
+// with gadget base `base`, drawing its randomness from src.
+func (params *Parameters) GenRelinearizationKey(sk *rlwe.SecretKey, base *big.Int, src negacyclic.Source) *RelinearizationKey {
+	s := sk.S.Polynomial()
+	s2 := params.ring.Mul(s, s)
+	ksk := rlwe.NewKeySwitchingKey(s2, base, params.Modulus(params.MaxLevel()), func(pt *negacyclic.Polynomial) *rlwe.Ciphertext {
+		return params.encryptSymmetric(sk, pt, src)
+	})
+	return &RelinearizationKey{ksk}
+}
+
+// Relinearize returns a ciphertext of degree 1 that encrypts the same
+// plaintext as the ciphertext of degree 2 ct. Its noise grows by Σ d_i·e_i,
+// where the d_i are the signed digits of c_2 in base B.
+func (params *Parameters) Relinearize(ct *Ciphertext, rlk *RelinearizationKey) *Ciphertext {
+	ring := params.ring.AtLevel(ct.Level)
+	return &Ciphertext{Value: rlk.Relinearize(ring, ring.Mod, ct.Value), Scale: ct.Scale, Level: ct.Level}
+}
+
+// Rescale returns the ciphertext ⌊ct/q_l⌉ at level l-1 and scale divided by
+// q_l, where l is the level of ct. It is computed modulo each remaining prime,
+// see negacyclic.RNSMultiplier.Rescale.
+func (params *Parameters) Rescale(ct *Ciphertext) *Ciphertext {
+	if ct.Level == 0 {
+		panic("cannot rescale below level 0")
+	}
+	ring := params.ring.AtLevel(ct.Level)
+	ql, _ := new(big.Float).SetInt(params.Moduli[ct.Level]).Float64()
+	rescaled := &Ciphertext{
+		Value: make([]*negacyclic.Polynomial, len(ct.Value)),
+		Scale: ct.Scale / ql,
+		Level: ct.Level - 1,
+	}
+	for i, c := range ct.Value {
+		rescaled.Value[i] = ring.Rescale(c)
+	}
+	return rescaled
+}
+
+// DropLevel returns ct reduced modulo Q_l for a level l lower than or equal to
+// its level, without changing its scale.
+func (params *Parameters) DropLevel(ct *Ciphertext, level int) *Ciphertext {
+	if level > ct.Level {
+		panic("cannot drop to a higher level")
+	}
+	dropped := &Ciphertext{Value: make([]*negacyclic.Polynomial, len(ct.Value)), Scale: ct.Scale, Level: level}
+	for i, c := range ct.Value {
+		dropped.Value[i] = c.Copy()
+	}
+	params.reduce(dropped)
+	return dropped
+}
+
+// encryptSymmetric returns the encryption (-a·s + e + pt, a) modulo Q_L of
+// the plaintext polynomial pt, where a and e are drawn from src.
+func (params *Parameters) encryptSymmetric(sk *rlwe.SecretKey, pt *negacyclic.Polynomial, src negacyclic.Source) *rlwe.Ciphertext {
+	e := params.Error.Sample(params.N, src).Polynomial()
+	return rlwe.EncryptSymmetricMod(params.ring, params.Modulus(params.MaxLevel()), sk, pt, e, src)
+}
+
+func (params *Parameters) reduce(ct *Ciphertext) {
+	q := params.Modulus(ct.Level)
+	for _, c := range ct.Value {
+		for _, coeff := range c.Coeffs {
+			coeff.Mod(coeff, q)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package ckks_test
+
+import (
+	"math"
+	"math/big"
+	"math/cmplx"
+	"testing"
+
+	"negacyclic"
+	"negacyclic/ckks"
+	"negacyclic/rlwe"
+)
+
+func TestCKKS(t *testing.T) {
+	t.Run("roundtrip", testRoundtrip)
+	t.Run("add", testAdd)
+	t.Run("mul_plain", testMulPlain)
+	t.Run("mul_rescale", testMulRescale)
+	t.Run("circuit", testCircuit)
+}
+
+const logScale = 40
+
+// testParameters uses a first prime of 60 bits for the precision of the
+// result, and primes of 41 bits close to the scale 2^40 for rescaling.
+func testParameters(levels int) (*ckks.Parameters, *ckks.Encoder) {
+	n := 1 << 8
+	moduli := []*big.Int{negacyclic.RLWEPrime(60, 2*n)}
+	moduli = append(moduli, negacyclic.RLWEPrimeChain(logScale+1, 2*n, levels)...)
+	params := ckks.NewParameters(n, moduli, rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 3.2})
+	return params, ckks.NewEncoder(n)
+}
+
+func testRoundtrip(t *testing.T) {
+	params, enc := testParameters(2)
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	x := randomSlots(enc.Slots())
+	ct := params.Encrypt(pk, enc.Encode(x, math.Exp2(logScale)), negacyclic.DefaultSource)
+	checkPrecision(t, x, enc.Decode(params.Decrypt(sk, ct)), 1e-8)
+}
+
+func testAdd(t *testing.T) {
+	params, enc := testParameters(2)
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	x, y := randomSlots(enc.Slots()), randomSlots(enc.Slots())
+	scale := math.Exp2(logScale)
+	ct := params.Add(params.Encrypt(pk, enc.Encode(x, scale), negacyclic.DefaultSource), params.Encrypt(pk, enc.Encode(y, scale), negacyclic.DefaultSource))
+	expected := make([]complex128, len(x))
+	for i := range x {
+		expected[i] = x[i] + y[i]
+	}
+	checkPrecision(t, expected, enc.Decode(params.Decrypt(sk, ct)), 1e-8)
+}
+
+func testMulPlain(t *testing.T) {
+	params, enc := testParameters(2)
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	x, y := randomSlots(enc.Slots()), randomSlots(enc.Slots())
+	scale := math.Exp2(logScale)
+	ct := params.MulPlain(params.Encrypt(pk, enc.Encode(x, scale), negacyclic.DefaultSource), enc.Encode(y, scale))
+	ct = params.Rescale(ct)
+	checkPrecision(t, product(x, y), enc.Decode(params.Decrypt(sk, ct)), 1e-7)
+}
+
+func testMulRescale(t *testing.T) {
+	params, enc := testParameters(2)
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	rlk := params.GenRelinearizationKey(sk, big.NewInt(1<<16), negacyclic.DefaultSource)
+	x, y := randomSlots(enc.Slots()), randomSlots(enc.Slots())
+	scale := math.Exp2(logScale)
+	prod := params.Mul(params.Encrypt(pk, enc.Encode(x, scale), negacyclic.DefaultSource), params.Encrypt(pk, enc.Encode(y, scale), negacyclic.DefaultSource))
+	prod = params.Rescale(params.Relinearize(prod, rlk))
+	if prod.Level != params.MaxLevel()-1 {
+		t.Fatalf("expected level %d, got %d", params.MaxLevel()-1, prod.Level)
+	}
+	ql, _ := new(big.Float).SetInt(params.Moduli[params.MaxLevel()]).Float64()
+	if expected := scale * scale / ql; prod.Scale != expected {
+		t.Fatalf("expected scale %g, got %g", expected, prod.Scale)
+	}
+	checkPrecision(t, product(x, y), enc.Decode(params.Decrypt(sk, prod)), 1e-7)
+}
+
+// testCircuit evaluates x^4 + y with two multiplications and rescalings.
+func testCircuit(t *testing.T) {
+	params, enc := testParameters(2)
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	rlk := params.GenRelinearizationKey(sk, big.NewInt(1<<16), negacyclic.DefaultSource)
+	x, y := randomSlots(enc.Slots()), randomSlots(enc.Slots())
+	scale := math.Exp2(logScale)
+	ct := params.Encrypt(pk, enc.Encode(x, scale), negacyclic.DefaultSource)
+	for i := 0; i < 2; i++ {
+		ct = params.Rescale(params.Relinearize(params.Mul(ct, ct), rlk))
+	}
+	ctY := params.Encrypt(pk, enc.Encode(y, ct.Scale), negacyclic.DefaultSource)
+	ct = params.Add(ct, params.DropLevel(ctY, ct.Level))
+	expected := make([]complex128, len(x))
+	for i := range x {
+		expected[i] = x[i]*x[i]*x[i]*x[i] + y[i]
+	}
+	checkPrecision(t, expected, enc.Decode(params.Decrypt(sk, ct)), 1e-6)
+}
+
+func product(x, y []complex128) []complex128 {
+	prod := make([]complex128, len(x))
+	for i := range x {
+		prod[i] = x[i] * y[i]
+	}
+	return prod
+}
+
+func checkPrecision(t *testing.T, expected, got []complex128, bound float64) {
+	t.Helper()
+	var maxErr float64
+	for i := range expected {
+		maxErr = math.Max(maxErr, cmplx.Abs(expected[i]-got[i]))
+	}
+	t.Logf("maximal error: 2^%.1f", math.Log2(maxErr))
+	if maxErr > bound {
+		t.Fatalf("error %g exceeds %g", maxErr, bound)
+	}
+}
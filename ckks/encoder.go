@@ -0,0 +1,176 @@
+// Package ckks implements the approximate homomorphic encryption scheme of
+// Cheon, Kim, Kim and Song (CKKS) over the negacyclic ring Z[X]/(X^N+1).
+//
+// Messages are vectors of N/2 complex numbers, called slots, identified with
+// the evaluations of a real polynomial at the primitive 2N-th roots of unity
+// ζ^{5^j}, j = 0, ..., N/2-1, with ζ = exp(iπ/N). This ordering makes the
+// automorphism X -> X^5 act as a cyclic rotation of the slots.
+//
+// A message is encoded with a scale Δ into an integer polynomial m, and
+// encrypted as an RLWE ciphertext of phase m + e modulo Q_l = q_0 ... q_l,
+// where l is the level of the ciphertext. Ciphertexts can be added and
+// multiplied, the scale of a product being the product of the scales.
+// Rescaling divides a ciphertext and its scale by q_l, with rounding, and
+// brings it to level l-1.
+package ckks
+
+import (
+	"math"
+	"math/big"
+
+	"negacyclic"
+)
+
+// Encoder maps vectors of N/2 complex numbers to integer polynomials of degree
+// N, through the inverse canonical embedding, and back.
+type Encoder struct {
+	N    int
+	prec uint
+	// slots[j] is the index of ζ^{5^j} in the canonical embedding, in which
+	// the evaluation at ζ^{2i+1} is stored at index i.
+	slots []int
+}
+
+// NewEncoder creates and returns an Encoder for the ring of degree n, working
+// in double precision.
+func NewEncoder(n int) *Encoder {
+	return NewEncoderWithPrecision(n, 0)
+}
+
+// NewEncoderWithPrecision creates and returns an Encoder for the ring of
+// degree n, working with arbitrary-precision floating-point numbers of prec
+// bits. A zero precision selects double precision arithmetic.
+func NewEncoderWithPrecision(n int, prec uint) *Encoder {
+	if n < 4 || n&(n-1) != 0 {
+		panic("encoder expects `n` power of two, n >= 4")
+	}
+	enc := new(Encoder)
+	enc.N = n
+	enc.prec = prec
+	enc.slots = make([]int, n/2)
+	pow := 1
+	for j := range enc.slots {
+		enc.slots[j] = (pow - 1) / 2
+		pow = (5 * pow) % (2 * n)
+	}
+	return enc
+}
+
+// Slots returns the number of complex slots, N/2.
+func (enc *Encoder) Slots() int {
+	return enc.N / 2
+}
+
+// Plaintext is an encoded message, together with the scale Δ used to encode
+// it: the slots of Value are Δ times the encoded values, up to rounding.
+type Plaintext struct {
+	Value *negacyclic.Polynomial
+	Scale float64
+}
+
+// Encode returns the plaintext ⌊Δ·p⌉, where p is the real polynomial whose
+// slots hold the given values, and Δ = scale. If less than N/2 values are
+// given, the remaining slots are set to zero.
+func (enc *Encoder) Encode(values []complex128, scale float64) *Plaintext {
+	if len(values) > enc.Slots() {
+		panic("too many values to encode")
+	}
+	if enc.prec == 0 {
+		return enc.encodeFloat(values, scale)
+	}
+	return enc.encodeBig(values, scale)
+}
+
+// EncodeReal is like Encode, for real values.
+func (enc *Encoder) EncodeReal(values []float64, scale float64) *Plaintext {
+	complexValues := make([]complex128, len(values))
+	for i, val := range values {
+		complexValues[i] = complex(val, 0)
+	}
+	return enc.Encode(complexValues, scale)
+}
+
+// Decode returns the N/2 slots of pt, divided by its scale. The coefficients
+// of the plaintext are interpreted as integers, so that plaintexts reduced
+// modulo q must be given in their symmetric representation (see
+// negacyclic.Polynomial.Mod).
+func (enc *Encoder) Decode(pt *Plaintext) []complex128 {
+	if pt.Value.Deg() != enc.N {
+		panic("plaintext of unexpected degree")
+	}
+	values := make([]complex128, enc.Slots())
+	if enc.prec == 0 {
+		emb := negacyclic.CanonicalEmbedding(pt.Value)
+		scale := complex(pt.Scale, 0)
+		for j, index := range enc.slots {
+			values[j] = emb[index] / scale
+		}
+		return values
+	}
+	emb := negacyclic.CanonicalEmbeddingBig(pt.Value, enc.prec)
+	scale := new(big.Float).SetPrec(enc.prec).SetFloat64(pt.Scale)
+	for j, index := range enc.slots {
+		emb[index].Re.Quo(emb[index].Re, scale)
+		emb[index].Im.Quo(emb[index].Im, scale)
+		values[j] = emb[index].Complex128()
+	}
+	return values
+}
+
+func (enc *Encoder) encodeFloat(values []complex128, scale float64) *Plaintext {
+	half := make([]complex128, enc.Slots())
+	for j, val := range values {
+		index, conj := enc.halfIndex(j)
+		if conj {
+			val = complex(real(val), -imag(val))
+		}
+		half[index] = val * complex(scale, 0)
+	}
+	coeffs := negacyclic.InverseCanonicalEmbedding(half)
+	pt := negacyclic.NewPolynomial(enc.N)
+	aux := new(big.Float)
+	for i, coeff := range coeffs {
+		aux.SetFloat64(math.Round(coeff))
+		aux.Int(pt.Coeffs[i])
+	}
+	return &Plaintext{Value: pt, Scale: scale}
+}
+
+func (enc *Encoder) encodeBig(values []complex128, scale float64) *Plaintext {
+	half := make([]*negacyclic.BigComplex, enc.Slots())
+	for i := range half {
+		half[i] = negacyclic.NewBigComplex(enc.prec)
+	}
+	bigScale := new(big.Float).SetPrec(enc.prec).SetFloat64(scale)
+	for j, val := range values {
+		index, conj := enc.halfIndex(j)
+		half[index].Re.SetFloat64(real(val)).Mul(half[index].Re, bigScale)
+		half[index].Im.SetFloat64(imag(val)).Mul(half[index].Im, bigScale)
+		if conj {
+			half[index].Im.Neg(half[index].Im)
+		}
+	}
+	coeffs := negacyclic.InverseCanonicalEmbeddingBig(half, enc.prec)
+	pt := negacyclic.NewPolynomial(enc.N)
+	half05 := big.NewFloat(0.5)
+	for i, coeff := range coeffs {
+		// ⌊x⌉ = ⌊x + 0.5⌋, where Int truncates towards zero.
+		coeff.Add(coeff, half05)
+		coeff.Int(pt.Coeffs[i])
+		if coeff.Sign() < 0 && !coeff.IsInt() {
+			pt.Coeffs[i].Sub(pt.Coeffs[i], big.NewInt(1))
+		}
+	}
+	return &Plaintext{Value: pt, Scale: scale}
+}
+
+// halfIndex returns the index of the half canonical embedding, i.e. of the
+// evaluations at ζ^{2i+1} for i < N/2, that determines slot j. If this index
+// holds the conjugate of the slot, conj is set.
+func (enc *Encoder) halfIndex(j int) (index int, conj bool) {
+	index = enc.slots[j]
+	if index < enc.Slots() {
+		return index, false
+	}
+	return enc.N - 1 - index, true
+}
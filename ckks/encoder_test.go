@@ -0,0 +1,105 @@
+package ckks_test
+
+import (
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+
+	"negacyclic/ckks"
+)
+
+func TestEncoder(t *testing.T) {
+	t.Run("error_bound", testEncodeErrorBound)
+	t.Run("error_bound_big", testEncodeErrorBoundBig)
+	t.Run("real_values", testEncodeReal)
+	t.Run("partial_slots", testEncodePartial)
+}
+
+// The rounding error e of the encoding has coefficients in [-1/2, 1/2], so
+// that its slots are bounded by ‖e‖_1 / Δ <= N / (2Δ).
+func encodingBound(n int, scale float64) float64 {
+	return float64(n) / (2 * scale)
+}
+
+func testEncodeErrorBound(t *testing.T) {
+	for _, logN := range []int{4, 8, 12} {
+		for _, logScale := range []int{10, 20, 30} {
+			n := 1 << logN
+			scale := math.Exp2(float64(logScale))
+			enc := ckks.NewEncoder(n)
+			values := randomSlots(enc.Slots())
+			got := enc.Decode(enc.Encode(values, scale))
+			maxErr := maxError(values, got)
+			if maxErr > encodingBound(n, scale) {
+				t.Errorf("N = %d, Δ = 2^%d: error %g exceeds %g",
+					n, logScale, maxErr, encodingBound(n, scale))
+			}
+		}
+	}
+}
+
+func testEncodeErrorBoundBig(t *testing.T) {
+	// Beyond 2^53, double precision does not suffice to round the
+	// coefficients.
+	n := 1 << 6
+	scale := math.Exp2(60)
+	enc := ckks.NewEncoderWithPrecision(n, 128)
+	values := randomSlots(enc.Slots())
+	pt := enc.Encode(values, scale)
+	got := enc.Decode(pt)
+	// The decoded values are rounded to double precision.
+	bound := encodingBound(n, scale) + 1e-15
+	if maxErr := maxError(values, got); maxErr > bound {
+		t.Errorf("error %g exceeds %g", maxErr, bound)
+	}
+}
+
+func testEncodeReal(t *testing.T) {
+	n := 1 << 8
+	scale := math.Exp2(30)
+	enc := ckks.NewEncoder(n)
+	values := make([]float64, enc.Slots())
+	for i := range values {
+		values[i] = 2*rand.Float64() - 1
+	}
+	got := enc.Decode(enc.EncodeReal(values, scale))
+	for i := range values {
+		if math.Abs(real(got[i])-values[i]) > encodingBound(n, scale) ||
+			math.Abs(imag(got[i])) > encodingBound(n, scale) {
+			t.Fatalf("slot %d: expected %f, got %v", i, values[i], got[i])
+		}
+	}
+}
+
+func testEncodePartial(t *testing.T) {
+	n := 1 << 8
+	scale := math.Exp2(30)
+	enc := ckks.NewEncoder(n)
+	values := randomSlots(10)
+	got := enc.Decode(enc.Encode(values, scale))
+	for i := len(values); i < len(got); i++ {
+		if cmplx.Abs(got[i]) > encodingBound(n, scale) {
+			t.Fatalf("slot %d: expected 0, got %v", i, got[i])
+		}
+	}
+	if maxErr := maxError(values, got[:len(values)]); maxErr > encodingBound(n, scale) {
+		t.Errorf("error %g exceeds %g", maxErr, encodingBound(n, scale))
+	}
+}
+
+func randomSlots(n int) []complex128 {
+	values := make([]complex128, n)
+	for i := range values {
+		values[i] = complex(2*rand.Float64()-1, 2*rand.Float64()-1)
+	}
+	return values
+}
+
+func maxError(expected, got []complex128) float64 {
+	var maxErr float64
+	for i := range expected {
+		maxErr = math.Max(maxErr, cmplx.Abs(expected[i]-got[i]))
+	}
+	return maxErr
+}
@@ -0,0 +1,158 @@
+package negacyclic
+
+import "math/big"
+
+// Compress returns the polynomial whose coefficients are ⌈(2^d/q)·x⌋ mod 2^d,
+// for the coefficients x of p reduced modulo q. Ties round up.
+func Compress(p *Polynomial, q *big.Int, d int) *Polynomial {
+	if d < 1 {
+		panic("compression expects d >= 1")
+	}
+	halfQ := new(big.Int).Rsh(q, 1)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(d)), big.NewInt(1))
+	res := NewPolynomial(p.Deg())
+	for i, coeff := range p.Coeffs {
+		// ⌊(2^d·x + ⌊q/2⌋)/q⌋ is the rounding of 2^d·x/q, half up.
+		x := res.Coeffs[i].Mod(coeff, q)
+		x.Lsh(x, uint(d)).Add(x, halfQ).Quo(x, q).And(x, mask)
+	}
+	return res
+}
+
+// Decompress returns the polynomial whose coefficients are ⌈(q/2^d)·y⌋, for
+// the coefficients y of p in [0, 2^d). Ties round up.
+func Decompress(p *Polynomial, q *big.Int, d int) *Polynomial {
+	if d < 1 {
+		panic("compression expects d >= 1")
+	}
+	half := new(big.Int).Lsh(big.NewInt(1), uint(d-1))
+	res := NewPolynomial(p.Deg())
+	for i, coeff := range p.Coeffs {
+		y := res.Coeffs[i].Mul(coeff, q)
+		y.Add(y, half).Rsh(y, uint(d))
+	}
+	return res
+}
+
+// CompressVector is the counterpart of Compress for a vector and a small
+// modulus q.
+func CompressVector(v *Vector, q, d int) *Vector {
+	if d < 1 {
+		panic("compression expects d >= 1")
+	}
+	res := NewVector(v.Len())
+	for i, x := range v.Coeffs {
+		x %= q
+		if x < 0 {
+			x += q
+		}
+		res.Coeffs[i] = ((x<<d + q/2) / q) & (1<<d - 1)
+	}
+	return res
+}
+
+// DecompressVector is the counterpart of Decompress for a vector and a small
+// modulus q.
+func DecompressVector(v *Vector, q, d int) *Vector {
+	if d < 1 {
+		panic("compression expects d >= 1")
+	}
+	res := NewVector(v.Len())
+	for i, y := range v.Coeffs {
+		res.Coeffs[i] = (y*q + 1<<(d-1)) >> d
+	}
+	return res
+}
+
+// Pack serializes the coefficients of p, which must lie in [0, 2^w), on w
+// bits each into exactly ⌈N·w/8⌉ bytes. Coefficients and their bits are
+// written in little-endian order, as in FIPS 203 and 204.
+func Pack(p *Polynomial, w int) []byte {
+	if w < 1 {
+		panic("packing expects w >= 1")
+	}
+	b := make([]byte, (p.Deg()*w+7)/8)
+	pos := 0
+	for _, coeff := range p.Coeffs {
+		if coeff.Sign() < 0 || coeff.BitLen() > w {
+			panic("coefficient does not fit in w bits")
+		}
+		for j := 0; j < w; j++ {
+			b[pos/8] |= byte(coeff.Bit(j)) << (pos % 8)
+			pos++
+		}
+	}
+	return b
+}
+
+// Unpack returns the polynomial of n coefficients of w bits each serialized
+// by Pack. It panics if b is not of length ⌈n·w/8⌉ or if its padding bits are
+// not zero.
+func Unpack(b []byte, n, w int) *Polynomial {
+	checkPacked(b, n, w)
+	p := NewPolynomial(n)
+	pos := 0
+	for _, coeff := range p.Coeffs {
+		for j := 0; j < w; j++ {
+			coeff.SetBit(coeff, j, uint(b[pos/8]>>(pos%8)&1))
+			pos++
+		}
+	}
+	return p
+}
+
+// PackVector is the counterpart of Pack for a vector. Coefficients may be
+// negative, down to -2^{w-1}, and are written modulo 2^w.
+func PackVector(v *Vector, w int) []byte {
+	if w < 1 || w > 62 {
+		panic("vector packing expects 1 <= w <= 62")
+	}
+	b := make([]byte, (v.Len()*w+7)/8)
+	pos := 0
+	for _, x := range v.Coeffs {
+		if x < -(1<<(w-1)) || x >= 1<<w {
+			panic("coefficient does not fit in w bits")
+		}
+		for j := 0; j < w; j++ {
+			b[pos/8] |= byte(x>>j&1) << (pos % 8)
+			pos++
+		}
+	}
+	return b
+}
+
+// UnpackVector returns the vector of n coefficients of w bits each serialized
+// by PackVector. If signed is set, the coefficients are sign-extended to
+// [-2^{w-1}, 2^{w-1}), otherwise they lie in [0, 2^w).
+func UnpackVector(b []byte, n, w int, signed bool) *Vector {
+	if w > 62 {
+		panic("vector packing expects 1 <= w <= 62")
+	}
+	checkPacked(b, n, w)
+	v := NewVector(n)
+	pos := 0
+	for i := range v.Coeffs {
+		x := 0
+		for j := 0; j < w; j++ {
+			x |= int(b[pos/8]>>(pos%8)&1) << j
+			pos++
+		}
+		if signed && x >= 1<<(w-1) {
+			x -= 1 << w
+		}
+		v.Coeffs[i] = x
+	}
+	return v
+}
+
+func checkPacked(b []byte, n, w int) {
+	if w < 1 {
+		panic("packing expects w >= 1")
+	}
+	if len(b) != (n*w+7)/8 {
+		panic("packed data of unexpected length")
+	}
+	if rem := n * w % 8; rem != 0 && b[len(b)-1]>>rem != 0 {
+		panic("non-zero padding bits")
+	}
+}
@@ -0,0 +1,130 @@
+package negacyclic_test
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"negacyclic"
+	"negacyclic/mlkem"
+)
+
+func TestCompress(t *testing.T) {
+	t.Run("mlkem", testCompressMLKEM)
+	t.Run("error_bound", testCompressErrorBound)
+}
+
+// testCompressMLKEM compares with the compression of FIPS 203.
+func testCompressMLKEM(t *testing.T) {
+	n := mlkem.N
+	v := negacyclic.NewVector(n)
+	for i := range v.Coeffs {
+		v.Coeffs[i] = rand.Intn(mlkem.Q)
+	}
+	q := big.NewInt(mlkem.Q)
+	for _, d := range []int{1, 4, 10, 11} {
+		expected := mlkem.CompressVector(v, d)
+		checkVectorsEqual(t, expected, negacyclic.CompressVector(v, mlkem.Q, d))
+		checkVectorsEqual(t, expected, vectorOf(negacyclic.Compress(v.Polynomial(), q, d)))
+		expected = mlkem.DecompressVector(expected, d)
+		checkVectorsEqual(t, expected, negacyclic.DecompressVector(negacyclic.CompressVector(v, mlkem.Q, d), mlkem.Q, d))
+		checkVectorsEqual(t, expected, vectorOf(negacyclic.Decompress(negacyclic.Compress(v.Polynomial(), q, d), q, d)))
+	}
+}
+
+func testCompressErrorBound(t *testing.T) {
+	n := 256
+	q := negacyclic.RLWEPrime(100, 2*n)
+	p := randomElement(n, q)
+	for _, d := range []int{1, 20, 64, 99} {
+		// |Decompress(Compress(x)) - x mod± q| <= ⌈q/2^{d+1}⌋.
+		bound := new(big.Int).Rsh(q, uint(d+1))
+		bound.Add(bound, big.NewInt(1))
+		compressed := negacyclic.Compress(p, q, d)
+		for _, coeff := range compressed.Coeffs {
+			if coeff.Sign() < 0 || coeff.BitLen() > d {
+				t.Fatalf("d = %d: compressed coefficient %d out of range", d, coeff)
+			}
+		}
+		diff := negacyclic.Sub(negacyclic.Decompress(compressed, q, d), p)
+		if norm := diff.InfNorm(q); norm.Cmp(bound) > 0 {
+			t.Fatalf("d = %d: error %d exceeds %d", d, norm, bound)
+		}
+	}
+}
+
+func TestPack(t *testing.T) {
+	t.Run("polynomial", testPackPolynomial)
+	t.Run("vector", testPackVector)
+	t.Run("padding", testPackPadding)
+}
+
+func testPackPolynomial(t *testing.T) {
+	n := 128
+	for _, w := range []int{1, 3, 12, 23, 64, 131} {
+		bound := new(big.Int).Lsh(big.NewInt(1), uint(w))
+		p := randomElement(n, bound)
+		b := negacyclic.Pack(p, w)
+		if len(b) != (n*w+7)/8 {
+			t.Fatalf("w = %d: expected %d bytes, got %d", w, (n*w+7)/8, len(b))
+		}
+		checkPolynomialsEqual(t, p, negacyclic.Unpack(b, n, w))
+	}
+}
+
+func testPackVector(t *testing.T) {
+	n := 100
+	for _, w := range []int{1, 3, 10, 18, 33} {
+		unsigned, signed := negacyclic.NewVector(n), negacyclic.NewVector(n)
+		for i := 0; i < n; i++ {
+			unsigned.Coeffs[i] = rand.Intn(1 << w)
+			signed.Coeffs[i] = rand.Intn(1<<w) - 1<<(w-1)
+		}
+		b := negacyclic.PackVector(unsigned, w)
+		if len(b) != (n*w+7)/8 {
+			t.Fatalf("w = %d: expected %d bytes, got %d", w, (n*w+7)/8, len(b))
+		}
+		checkVectorsEqual(t, unsigned, negacyclic.UnpackVector(b, n, w, false))
+		checkVectorsEqual(t, signed, negacyclic.UnpackVector(negacyclic.PackVector(signed, w), n, w, true))
+	}
+	// Small polynomials pack identically as vectors and polynomials.
+	v := negacyclic.NewVector(n)
+	for i := range v.Coeffs {
+		v.Coeffs[i] = rand.Intn(1 << 7)
+	}
+	if !bytes.Equal(negacyclic.PackVector(v, 7), negacyclic.Pack(v.Polynomial(), 7)) {
+		t.Fatal("vector and polynomial packings differ")
+	}
+}
+
+func testPackPadding(t *testing.T) {
+	b := negacyclic.PackVector(negacyclic.VectorFromSlice([]int{1, 2, 3}), 3)
+	b[1] |= 0x80
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic on non-zero padding bits")
+		}
+	}()
+	negacyclic.UnpackVector(b, 3, 3, false)
+}
+
+func checkVectorsEqual(t *testing.T, expected, got *negacyclic.Vector) {
+	t.Helper()
+	if expected.Len() != got.Len() {
+		t.Fatalf("expected length %d, got %d", expected.Len(), got.Len())
+	}
+	for i := range expected.Coeffs {
+		if expected.Coeffs[i] != got.Coeffs[i] {
+			t.Fatalf("coefficient %d: expected %d, got %d", i, expected.Coeffs[i], got.Coeffs[i])
+		}
+	}
+}
+
+func vectorOf(p *negacyclic.Polynomial) *negacyclic.Vector {
+	v := negacyclic.NewVector(p.Deg())
+	for i, coeff := range p.Coeffs {
+		v.Coeffs[i] = int(coeff.Int64())
+	}
+	return v
+}
@@ -0,0 +1,247 @@
+package negacyclic
+
+import (
+	"math"
+	"math/big"
+)
+
+// embeddingGuardBits is the number of extra bits of precision used internally
+// by the arbitrary-precision canonical embedding, to absorb the rounding errors
+// accumulated by the FFT.
+const embeddingGuardBits = 64
+
+// BigComplex is an arbitrary-precision complex number.
+type BigComplex struct {
+	Re, Im *big.Float
+}
+
+// NewBigComplex allocates and returns the complex number 0 with the given
+// precision.
+func NewBigComplex(prec uint) *BigComplex {
+	return &BigComplex{
+		Re: new(big.Float).SetPrec(prec),
+		Im: new(big.Float).SetPrec(prec),
+	}
+}
+
+// Complex128 returns the nearest complex128 value to z.
+func (z *BigComplex) Complex128() complex128 {
+	re, _ := z.Re.Float64()
+	im, _ := z.Im.Float64()
+	return complex(re, im)
+}
+
+// CanonicalEmbedding returns the evaluations of p at the primitive 2N-th
+// complex roots of unity ζ^{2j+1}, j = 0, ..., N-1, where ζ = exp(iπ/N) and N
+// is the degree of p. The evaluations at ζ^{2j+1} and ζ^{2(N-1-j)+1} are
+// complex conjugates. It uses a complex FFT in double precision.
+func CanonicalEmbedding(p *Polynomial) []complex128 {
+	n := p.Deg()
+	roots := complexRootsOfUnity(2 * n)
+	a := make([]complex128, n)
+	for k, coeff := range p.Coeffs {
+		// p(ζ^{2j+1}) = Σ (p_k ζ^k) (ζ^2)^{jk}, a DFT of the twisted
+		// coefficients.
+		c, _ := new(big.Float).SetInt(coeff).Float64()
+		a[k] = complex(c, 0) * roots[k]
+	}
+	fftComplex(a, roots, false)
+	return a
+}
+
+// InverseCanonicalEmbedding returns the real coefficients of the unique
+// polynomial p of degree N = 2*len(values) such that p(ζ^{2j+1}) = values[j]
+// for j = 0, ..., N/2-1. The remaining evaluations are implicitly the complex
+// conjugates of the given ones, so that p has real coefficients.
+func InverseCanonicalEmbedding(values []complex128) []float64 {
+	n := 2 * len(values)
+	if !isPowerOfTwo(n) {
+		panic("inverse embedding expects N/2 values, with N a power of two")
+	}
+	roots := complexRootsOfUnity(2 * n)
+	a := make([]complex128, n)
+	for j, val := range values {
+		a[j] = val
+		a[n-1-j] = complex(real(val), -imag(val))
+	}
+	fftComplex(a, roots, true)
+	coeffs := make([]float64, n)
+	for k := range coeffs {
+		// Untwist by ζ^{-k} = conj(ζ^k) and divide by N.
+		c := a[k] * complex(real(roots[k]), -imag(roots[k]))
+		coeffs[k] = real(c) / float64(n)
+	}
+	return coeffs
+}
+
+// CanonicalEmbeddingBig is the arbitrary-precision counterpart of
+// CanonicalEmbedding. The returned evaluations have precision prec.
+func CanonicalEmbeddingBig(p *Polynomial, prec uint) []*BigComplex {
+	n := p.Deg()
+	work := prec + embeddingGuardBits
+	roots := bigRootsOfUnity(2*n, work)
+	a := make([]*BigComplex, n)
+	for k, coeff := range p.Coeffs {
+		c := new(big.Float).SetPrec(work).SetInt(coeff)
+		a[k] = NewBigComplex(work)
+		a[k].Re.Mul(c, roots[k].Re)
+		a[k].Im.Mul(c, roots[k].Im)
+	}
+	fftBig(a, roots, false, work)
+	for _, val := range a {
+		val.Re.SetPrec(prec)
+		val.Im.SetPrec(prec)
+	}
+	return a
+}
+
+// InverseCanonicalEmbeddingBig is the arbitrary-precision counterpart of
+// InverseCanonicalEmbedding. The returned coefficients have precision prec.
+func InverseCanonicalEmbeddingBig(values []*BigComplex, prec uint) []*big.Float {
+	n := 2 * len(values)
+	if !isPowerOfTwo(n) {
+		panic("inverse embedding expects N/2 values, with N a power of two")
+	}
+	work := prec + embeddingGuardBits
+	roots := bigRootsOfUnity(2*n, work)
+	a := make([]*BigComplex, n)
+	for j, val := range values {
+		a[j] = NewBigComplex(work)
+		a[j].Re.Set(val.Re)
+		a[j].Im.Set(val.Im)
+		a[n-1-j] = NewBigComplex(work)
+		a[n-1-j].Re.Set(val.Re)
+		a[n-1-j].Im.Neg(val.Im)
+	}
+	fftBig(a, roots, true, work)
+	coeffs := make([]*big.Float, n)
+	nFloat := new(big.Float).SetPrec(work).SetInt64(int64(n))
+	aux := new(big.Float).SetPrec(work)
+	for k := range coeffs {
+		// Re(a_k * conj(ζ^k)) = Re(a_k)Re(ζ^k) + Im(a_k)Im(ζ^k).
+		coeffs[k] = new(big.Float).SetPrec(work)
+		coeffs[k].Mul(a[k].Re, roots[k].Re)
+		aux.Mul(a[k].Im, roots[k].Im)
+		coeffs[k].Add(coeffs[k], aux).Quo(coeffs[k], nFloat)
+		coeffs[k].SetPrec(prec)
+	}
+	return coeffs
+}
+
+//
+// Internal
+//
+
+// complexRootsOfUnity returns the slice of ζ^k for k = 0, ..., m-1, with
+// ζ = exp(2iπ/m).
+func complexRootsOfUnity(m int) []complex128 {
+	roots := make([]complex128, m)
+	for k := range roots {
+		angle := 2 * math.Pi * float64(k) / float64(m)
+		roots[k] = complex(math.Cos(angle), math.Sin(angle))
+	}
+	return roots
+}
+
+// fftComplex computes in place the DFT X_j = Σ a_k ω^{±jk} of length n =
+// len(a), where ω = roots[2] is a primitive n-th root of unity and roots holds
+// the 2n-th roots of unity. The sign of the exponent is negative iff inverse
+// is set. The result is not normalized.
+func fftComplex(a []complex128, roots []complex128, inverse bool) {
+	n := len(a)
+	for i := 0; i < n; i++ {
+		j := int(reverseBits(i, n))
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	m := len(roots)
+	for size := 2; size <= n; size *= 2 {
+		step := m / size
+		for start := 0; start < n; start += size {
+			for k := 0; k < size/2; k++ {
+				w := roots[k*step]
+				if inverse {
+					w = complex(real(w), -imag(w))
+				}
+				u := a[start+k]
+				v := a[start+k+size/2] * w
+				a[start+k] = u + v
+				a[start+k+size/2] = u - v
+			}
+		}
+	}
+}
+
+// bigRootsOfUnity returns the slice of ζ^k for k = 0, ..., m-1, with
+// ζ = exp(2iπ/m), computed with the given precision. It expects m to be a
+// power of two, m >= 2.
+func bigRootsOfUnity(m int, prec uint) []*BigComplex {
+	// Starting from exp(iπ) = -1, halve the angle until reaching 2π/m with
+	// cos(θ/2) = sqrt((1 + cos θ)/2) and sin(θ/2) = sin θ / (2 cos(θ/2)).
+	half := new(big.Float).SetPrec(prec).SetFloat64(0.5)
+	zeta := NewBigComplex(prec)
+	zeta.Re.SetInt64(-1)
+	for k := 2; k < m; k *= 2 {
+		cos := new(big.Float).SetPrec(prec).SetInt64(1)
+		cos.Add(cos, zeta.Re).Mul(cos, half).Sqrt(cos)
+		sin := new(big.Float).SetPrec(prec)
+		if k == 2 {
+			sin.SetInt64(1)
+		} else {
+			sin.Quo(zeta.Im, cos).Mul(sin, half)
+		}
+		zeta.Re, zeta.Im = cos, sin
+	}
+	roots := make([]*BigComplex, m)
+	roots[0] = NewBigComplex(prec)
+	roots[0].Re.SetInt64(1)
+	for k := 1; k < m; k++ {
+		roots[k] = NewBigComplex(prec)
+		mulBigComplex(roots[k], roots[k-1], zeta, prec)
+	}
+	return roots
+}
+
+// fftBig is the arbitrary-precision counterpart of fftComplex.
+func fftBig(a []*BigComplex, roots []*BigComplex, inverse bool, prec uint) {
+	n := len(a)
+	for i := 0; i < n; i++ {
+		j := int(reverseBits(i, n))
+		if i < j {
+			a[i], a[j] = a[j], a[i]
+		}
+	}
+	m := len(roots)
+	w, v := NewBigComplex(prec), NewBigComplex(prec)
+	for size := 2; size <= n; size *= 2 {
+		step := m / size
+		for start := 0; start < n; start += size {
+			for k := 0; k < size/2; k++ {
+				w.Re.Set(roots[k*step].Re)
+				w.Im.Set(roots[k*step].Im)
+				if inverse {
+					w.Im.Neg(w.Im)
+				}
+				u := a[start+k]
+				mulBigComplex(v, a[start+k+size/2], w, prec)
+				a[start+k+size/2].Re.Sub(u.Re, v.Re)
+				a[start+k+size/2].Im.Sub(u.Im, v.Im)
+				u.Re.Add(u.Re, v.Re)
+				u.Im.Add(u.Im, v.Im)
+			}
+		}
+	}
+}
+
+// mulBigComplex sets z = x*y. It is safe for z to alias x or y.
+func mulBigComplex(z, x, y *BigComplex, prec uint) {
+	re := new(big.Float).SetPrec(prec).Mul(x.Re, y.Re)
+	aux := new(big.Float).SetPrec(prec).Mul(x.Im, y.Im)
+	re.Sub(re, aux)
+	im := new(big.Float).SetPrec(prec).Mul(x.Re, y.Im)
+	aux.Mul(x.Im, y.Re)
+	im.Add(im, aux)
+	z.Re.Set(re)
+	z.Im.Set(im)
+}
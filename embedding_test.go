@@ -0,0 +1,106 @@
+package negacyclic_test
+
+import (
+	"math"
+	"math/big"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+
+	"negacyclic"
+)
+
+func TestCanonicalEmbedding(t *testing.T) {
+	t.Run("evaluation", testEmbeddingEvaluation)
+	t.Run("conjugate_symmetry", testEmbeddingConjugates)
+	t.Run("roundtrip", testEmbeddingRoundtrip)
+	t.Run("big_evaluation", testEmbeddingBigEvaluation)
+	t.Run("big_roundtrip", testEmbeddingBigRoundtrip)
+}
+
+func testEmbeddingEvaluation(t *testing.T) {
+	n := 64
+	p := smallElement(n, 1000)
+	emb := negacyclic.CanonicalEmbedding(p)
+	for j := 0; j < n; j++ {
+		root := cmplx.Exp(complex(0, math.Pi*float64(2*j+1)/float64(n)))
+		expected := evaluate(p, root)
+		if cmplx.Abs(expected-emb[j]) > 1e-8 {
+			t.Fatalf("p(ζ^%d) = %v, got %v", 2*j+1, expected, emb[j])
+		}
+	}
+}
+
+func testEmbeddingConjugates(t *testing.T) {
+	n := 128
+	p := smallElement(n, 1000)
+	emb := negacyclic.CanonicalEmbedding(p)
+	for j := 0; j < n; j++ {
+		if cmplx.Abs(emb[j]-cmplx.Conj(emb[n-1-j])) > 1e-8 {
+			t.Fatalf("evaluations %d and %d are not conjugate", j, n-1-j)
+		}
+	}
+}
+
+func testEmbeddingRoundtrip(t *testing.T) {
+	n := 1 << 10
+	p := smallElement(n, 1<<20)
+	emb := negacyclic.CanonicalEmbedding(p)
+	coeffs := negacyclic.InverseCanonicalEmbedding(emb[:n/2])
+	for i, coeff := range coeffs {
+		if math.Abs(coeff-float64(p.Coeffs[i].Int64())) > 1e-6 {
+			t.Fatalf("coefficient %d: expected %d, got %f", i, p.Coeffs[i], coeff)
+		}
+	}
+}
+
+func testEmbeddingBigEvaluation(t *testing.T) {
+	n := 32
+	p := smallElement(n, 1000)
+	emb := negacyclic.CanonicalEmbedding(p)
+	embBig := negacyclic.CanonicalEmbeddingBig(p, 128)
+	for j := range emb {
+		if cmplx.Abs(emb[j]-embBig[j].Complex128()) > 1e-8 {
+			t.Fatalf("evaluation %d: double precision %v, arbitrary precision %v",
+				j, emb[j], embBig[j].Complex128())
+		}
+	}
+}
+
+func testEmbeddingBigRoundtrip(t *testing.T) {
+	// Coefficients of 150 bits cannot be recovered in double precision.
+	n := 64
+	var prec uint = 256
+	bound := new(big.Int).Lsh(big.NewInt(1), 150)
+	p := randomElement(n, bound)
+	emb := negacyclic.CanonicalEmbeddingBig(p, prec)
+	coeffs := negacyclic.InverseCanonicalEmbeddingBig(emb[:n/2], prec)
+	half := big.NewFloat(0.5)
+	for i, coeff := range coeffs {
+		coeff.Add(coeff, half)
+		rounded, _ := coeff.Int(nil)
+		if rounded.Cmp(p.Coeffs[i]) != 0 {
+			t.Fatalf("coefficient %d: expected %d, got %d", i, p.Coeffs[i], rounded)
+		}
+	}
+}
+
+// smallElement returns a polynomial with coefficients drawn uniformly in
+// [-bound, bound).
+func smallElement(dim, bound int) *negacyclic.Polynomial {
+	pol := negacyclic.NewPolynomial(dim)
+	for i := range pol.Coeffs {
+		pol.Coeffs[i].SetInt64(int64(rand.Intn(2*bound) - bound))
+	}
+	return pol
+}
+
+func evaluate(p *negacyclic.Polynomial, x complex128) complex128 {
+	var res complex128
+	pow := complex(1, 0)
+	for _, coeff := range p.Coeffs {
+		res += complex(float64(coeff.Int64()), 0) * pow
+		pow *= x
+	}
+	return res
+}
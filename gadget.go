@@ -0,0 +1,105 @@
+package negacyclic
+
+import "math/big"
+
+// Decompose returns the signed base-B decomposition (d_0, ..., d_{l-1}) of p,
+// for B = base and l = levels, such that p = Σ B^i d_i. The coefficients of
+// the digits d_0, ..., d_{l-2} lie in [-B/2, B/2), and the most significant
+// digit absorbs the remaining carry. If every coefficient of p lies in
+// [-B^l/2, B^l/2], for instance p reduced with Polynomial.Mod modulo q <= B^l,
+// the most significant digit lies in [-B/2, B/2] as well. It does not modify
+// p.
+func Decompose(p *Polynomial, base *big.Int, levels int) []*Polynomial {
+	if base.Cmp(big.NewInt(2)) < 0 {
+		panic("decomposition expects base >= 2")
+	}
+	if levels < 1 {
+		panic("decomposition expects at least one level")
+	}
+	n := p.Deg()
+	digits := make([]*Polynomial, levels)
+	for i := range digits {
+		digits[i] = NewPolynomial(n)
+	}
+	c, twiceDigit := new(big.Int), new(big.Int)
+	for j, coeff := range p.Coeffs {
+		c.Set(coeff)
+		for i := 0; i < levels-1; i++ {
+			d := digits[i].Coeffs[j]
+			d.Mod(c, base)
+			if twiceDigit.Lsh(d, 1).Cmp(base) >= 0 {
+				d.Sub(d, base)
+			}
+			c.Sub(c, d).Quo(c, base)
+		}
+		digits[levels-1].Coeffs[j].Set(c)
+	}
+	return digits
+}
+
+// DecomposeVector is like Decompose, for a small base, and returns the digits
+// as vectors. It panics if a digit overflows an int.
+func DecomposeVector(p *Polynomial, base int, levels int) []*Vector {
+	digits := Decompose(p, big.NewInt(int64(base)), levels)
+	vecs := make([]*Vector, levels)
+	for i, digit := range digits {
+		vecs[i] = NewVector(digit.Deg())
+		for j, coeff := range digit.Coeffs {
+			if !coeff.IsInt64() || int64(int(coeff.Int64())) != coeff.Int64() {
+				panic("digit overflows int")
+			}
+			vecs[i].Coeffs[j] = int(coeff.Int64())
+		}
+	}
+	return vecs
+}
+
+// Recompose returns Σ B^i d_i for the given digits d_i and base B.
+func Recompose(digits []*Polynomial, base *big.Int) *Polynomial {
+	return RecomposeApprox(digits, base, 0)
+}
+
+// DecomposeApprox is the approximate variant of Decompose, which drops the
+// `dropped` least significant digits. It returns the digits
+// (d_dropped, ..., d_{l-1}) of the signed base-B decomposition of the nearest
+// multiple of B^dropped to each coefficient of p, so that p is approximated
+// by Σ B^i d_i for i >= dropped, up to an error of B^dropped/2 per
+// coefficient.
+func DecomposeApprox(p *Polynomial, base *big.Int, levels, dropped int) []*Polynomial {
+	if dropped < 0 || dropped >= levels {
+		panic("approximate decomposition expects 0 <= dropped < levels")
+	}
+	if dropped == 0 {
+		return Decompose(p, base, levels)
+	}
+	scale := new(big.Int).Exp(base, big.NewInt(int64(dropped)), nil)
+	half := new(big.Int).Rsh(scale, 1)
+	rounded := NewPolynomial(p.Deg())
+	for i, coeff := range p.Coeffs {
+		// ⌊c/B^k⌉ = ⌊(c + ⌊B^k/2⌋)/B^k⌋, with Euclidean division.
+		rounded.Coeffs[i].Add(coeff, half).Div(rounded.Coeffs[i], scale)
+	}
+	return Decompose(rounded, base, levels-dropped)
+}
+
+// RecomposeApprox returns Σ B^{dropped+i} d_i for the given digits d_i and
+// base B, which inverts DecomposeApprox up to its approximation error.
+func RecomposeApprox(digits []*Polynomial, base *big.Int, dropped int) *Polynomial {
+	if len(digits) == 0 {
+		panic("recomposition expects at least one digit")
+	}
+	n := digits[0].Deg()
+	result := NewPolynomial(n)
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i].Deg() != n {
+			panic("incompatible digits")
+		}
+		// Horner's rule.
+		for j, coeff := range result.Coeffs {
+			coeff.Mul(coeff, base).Add(coeff, digits[i].Coeffs[j])
+		}
+	}
+	scale := new(big.Int).Exp(base, big.NewInt(int64(dropped)), nil)
+	result.Scale(scale)
+	return result
+}
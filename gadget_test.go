@@ -0,0 +1,97 @@
+package negacyclic_test
+
+import (
+	"math/big"
+	"testing"
+
+	"negacyclic"
+)
+
+func TestGadgetDecomposition(t *testing.T) {
+	t.Run("recompose", testDecomposeRecompose)
+	t.Run("digit_range", testDecomposeDigitRange)
+	t.Run("vector", testDecomposeVector)
+	t.Run("approximate", testDecomposeApprox)
+}
+
+func testDecomposeRecompose(t *testing.T) {
+	n := 64
+	q := negacyclic.RLWEPrime(100, 2*n)
+	p := randomElement(n, q)
+	// Uncentered coefficients overflow into the most significant digit.
+	for _, base := range []int64{2, 3, 16, 1 << 20} {
+		b := big.NewInt(base)
+		digits := negacyclic.Decompose(p, b, 4)
+		rec := negacyclic.Recompose(digits, b)
+		for i := range p.Coeffs {
+			if rec.Coeffs[i].Cmp(p.Coeffs[i]) != 0 {
+				t.Fatalf("base %d: recomposed %d, expected %d", base, rec.Coeffs[i], p.Coeffs[i])
+			}
+		}
+	}
+}
+
+func testDecomposeDigitRange(t *testing.T) {
+	n := 256
+	for _, base := range []int64{3, 16, 1 << 10} {
+		b := big.NewInt(base)
+		levels := 6
+		q := new(big.Int).Exp(b, big.NewInt(int64(levels)), nil)
+		q.Sub(q, big.NewInt(1))
+		p := randomElement(n, q).Mod(q)
+		digits := negacyclic.Decompose(p, b, levels)
+		low, high := big.NewInt(-base/2), big.NewInt((base-1)/2)
+		for i, digit := range digits {
+			if i == levels-1 {
+				high.SetInt64(base / 2)
+			}
+			for _, coeff := range digit.Coeffs {
+				if coeff.Cmp(low) < 0 || coeff.Cmp(high) > 0 {
+					t.Fatalf("base %d: digit %d has coefficient %d", base, i, coeff)
+				}
+			}
+		}
+		rec := negacyclic.Recompose(digits, b)
+		for i := range p.Coeffs {
+			if rec.Coeffs[i].Cmp(p.Coeffs[i]) != 0 {
+				t.Fatalf("base %d: recomposed %d, expected %d", base, rec.Coeffs[i], p.Coeffs[i])
+			}
+		}
+	}
+}
+
+func testDecomposeVector(t *testing.T) {
+	n := 64
+	base, levels := 1<<8, 4
+	q := big.NewInt(1 << 32)
+	p := randomElement(n, q).Mod(q)
+	digits := negacyclic.Decompose(p, big.NewInt(int64(base)), levels)
+	vecs := negacyclic.DecomposeVector(p, base, levels)
+	for i := range digits {
+		for j := range digits[i].Coeffs {
+			if digits[i].Coeffs[j].Int64() != int64(vecs[i].Coeffs[j]) {
+				t.Fatalf("digit %d, coefficient %d: %d != %d", i, j, vecs[i].Coeffs[j], digits[i].Coeffs[j])
+			}
+		}
+	}
+}
+
+func testDecomposeApprox(t *testing.T) {
+	n := 64
+	b := big.NewInt(1 << 7)
+	levels, dropped := 5, 2
+	q := big.NewInt(1 << 35)
+	p := randomElement(n, q).Mod(q)
+	digits := negacyclic.DecomposeApprox(p, b, levels, dropped)
+	if len(digits) != levels-dropped {
+		t.Fatalf("expected %d digits, got %d", levels-dropped, len(digits))
+	}
+	rec := negacyclic.RecomposeApprox(digits, b, dropped)
+	bound := big.NewInt(1 << 13) // B^dropped / 2
+	diff := new(big.Int)
+	for i := range p.Coeffs {
+		if diff.Sub(rec.Coeffs[i], p.Coeffs[i]).CmpAbs(bound) > 0 {
+			t.Fatalf("approximation error %d exceeds %d", diff, bound)
+		}
+	}
+}
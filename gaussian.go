@@ -0,0 +1,134 @@
+package negacyclic
+
+import (
+	"math"
+	"math/big"
+)
+
+const (
+	// maxCDTSize bounds the number of entries of a GaussianCDT table.
+	maxCDTSize = 1 << 16
+	// cdtPrecision is the precision of the computation of GaussianCDT
+	// tables, far above the 63 bits of their entries.
+	cdtPrecision = 128
+)
+
+// GaussianCDT samples the discrete Gaussian distribution D_{Z,σ} over the
+// integers, of probability proportional to exp(-x²/(2σ²)), truncated to
+// [-τσ, τσ] for the tail cut τ. It uses a cumulative distribution table of |x|
+// in 63-bit fixed point, computed in 128-bit precision so that every entry is
+// correctly rounded, and scanned in full for every sample, so that the running
+// time does not depend on the output. It is meant for small σ, since the table
+// has ⌈τσ⌉+1 entries.
+type GaussianCDT struct {
+	Sigma   float64
+	TailCut float64
+	// table[k] = ⌊2^63·P(|x| <= k)⌉, with table[bound] = 2^63.
+	table []uint64
+}
+
+// NewGaussianCDT returns the CDT sampler of standard deviation σ and tail cut
+// τ, for instance τ = 6, which cuts a tail of mass about 2^-29 from D_{Z,σ}.
+func NewGaussianCDT(sigma, tailCut float64) *GaussianCDT {
+	if sigma <= 0 || tailCut <= 0 {
+		panic("Gaussian parameters must be positive")
+	}
+	bound := int(math.Ceil(sigma * tailCut))
+	if bound >= maxCDTSize {
+		panic("σ too large for a CDT sampler")
+	}
+	// Half-distribution of |x|: weights ρ(0) and 2ρ(k) for k >= 1, with
+	// ρ(k) = exp(-k²/(2σ²)), computed as ρ(k+1) = ρ(k)·c·d^k for
+	// c = exp(-1/(2σ²)) and d = exp(-1/σ²).
+	twoVar := new(big.Float).SetPrec(cdtPrecision).SetFloat64(sigma)
+	twoVar.Mul(twoVar, twoVar).Mul(twoVar, big.NewFloat(2))
+	step := expNeg(new(big.Float).SetPrec(cdtPrecision).Quo(big.NewFloat(1), twoVar))
+	ratio := new(big.Float).Mul(step, step)
+	weights := make([]*big.Float, bound+1)
+	rho := new(big.Float).SetPrec(cdtPrecision).SetInt64(1)
+	total := new(big.Float).SetPrec(cdtPrecision)
+	for k := range weights {
+		weights[k] = new(big.Float).Set(rho)
+		if k > 0 {
+			weights[k].Mul(weights[k], big.NewFloat(2))
+		}
+		total.Add(total, weights[k])
+		rho.Mul(rho, step)
+		step.Mul(step, ratio)
+	}
+	// table[k] = ⌊2^63·cumulative/total⌉.
+	scale := new(big.Float).SetPrec(cdtPrecision).SetUint64(1 << 63)
+	scale.Quo(scale, total)
+	table := make([]uint64, bound+1)
+	cumulative := new(big.Float).SetPrec(cdtPrecision)
+	entry := new(big.Float).SetPrec(cdtPrecision)
+	for k := range table {
+		cumulative.Add(cumulative, weights[k])
+		entry.Mul(cumulative, scale).Add(entry, big.NewFloat(.5))
+		table[k], _ = entry.Uint64()
+	}
+	table[bound] = 1 << 63
+	return &GaussianCDT{Sigma: sigma, TailCut: tailCut, table: table}
+}
+
+// Bound returns the largest absolute value output by the sampler, ⌈τσ⌉.
+func (g *GaussianCDT) Bound() int {
+	return len(g.table) - 1
+}
+
+// Sample returns a sample of the truncated D_{Z,σ}, drawn from src.
+func (g *GaussianCDT) Sample(src Source) int {
+	r := randUint64(src)
+	sign := int(r >> 63)
+	r &= 1<<63 - 1
+	// |x| is the number of entries less than or equal to r. Both operands
+	// are below 2^63, so the top bit of r - table[k] is set iff r < table[k].
+	k := 0
+	for _, t := range g.table {
+		k += int(1 ^ (r-t)>>63)
+	}
+	// x = ±k, branch-free.
+	return k - 2*sign*k
+}
+
+// SampleVector returns a vector of dim independent samples.
+func (g *GaussianCDT) SampleVector(dim int, src Source) *Vector {
+	v := NewVector(dim)
+	for i := range v.Coeffs {
+		v.Coeffs[i] = g.Sample(src)
+	}
+	return v
+}
+
+// DiscreteGaussian returns a vector of dim samples of D_{Z,σ} truncated at τσ,
+// drawn from src with a GaussianCDT. Unlike DG, σ is the standard deviation,
+// and the distribution is the discrete Gaussian, not a rounded normal.
+func DiscreteGaussian(dim int, sigma, tailCut float64, src Source) *Vector {
+	return NewGaussianCDT(sigma, tailCut).SampleVector(dim, src)
+}
+
+// expNeg returns exp(-x) for x >= 0, at the precision of x, as 1/exp(x) with
+// exp(x) = exp(x/2^r)^(2^r) and the Taylor series of exp for x/2^r < 1.
+func expNeg(x *big.Float) *big.Float {
+	prec := x.Prec() + 32
+	y := new(big.Float).SetPrec(prec).Set(x)
+	r := 0
+	if exp := y.MantExp(nil); exp > 0 {
+		r = exp
+		y.SetMantExp(y, -r)
+	}
+	sum := new(big.Float).SetPrec(prec).SetInt64(1)
+	term := new(big.Float).SetPrec(prec).SetInt64(1)
+	for i := int64(1); ; i++ {
+		term.Mul(term, y).Quo(term, new(big.Float).SetInt64(i))
+		if term.Sign() == 0 || term.MantExp(nil) < -int(prec) {
+			break
+		}
+		sum.Add(sum, term)
+	}
+	for ; r > 0; r-- {
+		sum.Mul(sum, sum)
+	}
+	res := new(big.Float).SetPrec(x.Prec()).SetInt64(1)
+	return res.Quo(res, sum)
+}
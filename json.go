@@ -0,0 +1,292 @@
+package negacyclic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MarshalJSON implements json.Marshaler. The coefficients are encoded as an
+// array of decimal strings, since they may exceed the precision of JSON
+// numbers.
+func (p *Polynomial) MarshalJSON() ([]byte, error) {
+	coeffs := make([]string, p.Deg())
+	for i, coeff := range p.Coeffs {
+		coeffs[i] = coeff.String()
+	}
+	return json.Marshal(coeffs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts an array of integers,
+// given as JSON numbers or as strings in decimal or in hexadecimal with the
+// prefix 0x, and a length that is a power of two.
+func (p *Polynomial) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if !isPowerOfTwo(len(raw)) {
+		return fmt.Errorf("%w: degree %d", ErrInvalidEncoding, len(raw))
+	}
+	coeffs := make([]*big.Int, len(raw))
+	for i, r := range raw {
+		coeff, err := decodeJSONInt(r)
+		if err != nil {
+			return err
+		}
+		coeffs[i] = coeff
+	}
+	p.Coeffs = coeffs
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, with the format of String
+// without the trailing space, e.g. "[1 -2 3 0]".
+func (p *Polynomial) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, coeff := range p.Coeffs {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(coeff.String())
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It parses the outputs of
+// MarshalText and String, with decimal or 0x-prefixed hexadecimal
+// coefficients.
+func (p *Polynomial) UnmarshalText(text []byte) error {
+	fields, err := textFields(text)
+	if err != nil {
+		return err
+	}
+	if !isPowerOfTwo(len(fields)) {
+		return fmt.Errorf("%w: degree %d", ErrInvalidEncoding, len(fields))
+	}
+	coeffs := make([]*big.Int, len(fields))
+	for i, field := range fields {
+		if coeffs[i], err = parseInt(field); err != nil {
+			return err
+		}
+	}
+	p.Coeffs = coeffs
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding v as an array of numbers.
+func (v *Vector) MarshalJSON() ([]byte, error) {
+	coeffs := v.Coeffs
+	if coeffs == nil {
+		coeffs = []int{}
+	}
+	return json.Marshal(coeffs)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Vector) UnmarshalJSON(data []byte) error {
+	var coeffs []int
+	if err := json.Unmarshal(data, &coeffs); err != nil {
+		return err
+	}
+	if coeffs == nil {
+		return fmt.Errorf("%w: expected an array", ErrInvalidEncoding)
+	}
+	v.Coeffs = coeffs
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, e.g. "[1 -1 0]".
+func (v *Vector) MarshalText() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, x := range v.Coeffs {
+		if i > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(strconv.Itoa(x))
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *Vector) UnmarshalText(text []byte) error {
+	fields, err := textFields(text)
+	if err != nil {
+		return err
+	}
+	coeffs := make([]int, len(fields))
+	for i, field := range fields {
+		if coeffs[i], err = strconv.Atoi(field); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+		}
+	}
+	v.Coeffs = coeffs
+	return nil
+}
+
+// multiplierJSON holds the parameters of a Multiplier. The root is optional.
+type multiplierJSON struct {
+	N       int             `json:"n"`
+	Modulus json.RawMessage `json:"modulus"`
+	Root    json.RawMessage `json:"root,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, as an object with N, the modulus and
+// the primitive root of unity, e.g. {"n":1024,"modulus":"12289","root":"..."}.
+func (mul *Multiplier) MarshalJSON() ([]byte, error) {
+	return json.Marshal(multiplierJSON{
+		N:       mul.N,
+		Modulus: quote(mul.Mod),
+		Root:    quote(mul.Root()),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Without a root, it rebuilds the
+// multiplier of NewMultiplier.
+func (mul *Multiplier) UnmarshalJSON(data []byte) error {
+	var params multiplierJSON
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+	res, err := multiplierFromJSON(params.N, params.Modulus, params.Root)
+	if err != nil {
+		return err
+	}
+	*mul = *res
+	return nil
+}
+
+// crtMultiplierJSON holds the parameters of a CRTMultiplier. The roots are
+// optional.
+type crtMultiplierJSON struct {
+	N      int               `json:"n"`
+	Moduli []json.RawMessage `json:"moduli"`
+	Roots  []json.RawMessage `json:"roots,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, as an object with N, the two primes
+// and their primitive roots of unity.
+func (m *CRTMultiplier) MarshalJSON() ([]byte, error) {
+	return json.Marshal(crtMultiplierJSON{
+		N:      m.N,
+		Moduli: []json.RawMessage{quote(m.multiplierP.Mod), quote(m.multiplierQ.Mod)},
+		Roots:  []json.RawMessage{quote(m.multiplierP.Root()), quote(m.multiplierQ.Root())},
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Without roots, it rebuilds the
+// multiplier of NewCRTMultiplier.
+func (m *CRTMultiplier) UnmarshalJSON(data []byte) error {
+	var params crtMultiplierJSON
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+	if len(params.Moduli) != 2 || (params.Roots != nil && len(params.Roots) != 2) {
+		return fmt.Errorf("%w: expected two moduli", ErrInvalidEncoding)
+	}
+	var multipliers [2]*Multiplier
+	for i, mod := range params.Moduli {
+		var root json.RawMessage
+		if params.Roots != nil {
+			root = params.Roots[i]
+		}
+		var err error
+		if multipliers[i], err = multiplierFromJSON(params.N, mod, root); err != nil {
+			return err
+		}
+	}
+	p, q := multipliers[0].Mod, multipliers[1].Mod
+	if p.Cmp(q) == 0 {
+		return fmt.Errorf("%w: equal moduli", ErrInvalidEncoding)
+	}
+	*m = CRTMultiplier{
+		PQ:          new(big.Int).Mul(p, q),
+		N:           params.N,
+		pInvQ:       modularInverse(p, q),
+		multiplierP: multipliers[0],
+		multiplierQ: multipliers[1],
+	}
+	return nil
+}
+
+//
+// Internal
+//
+
+func multiplierFromJSON(n int, rawMod, rawRoot json.RawMessage) (*Multiplier, error) {
+	if rawMod == nil {
+		return nil, fmt.Errorf("%w: missing modulus", ErrInvalidEncoding)
+	}
+	mod, err := decodeJSONInt(rawMod)
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 {
+		return nil, fmt.Errorf("%w: degree %d", ErrInvalidEncoding, n)
+	}
+	if rawRoot == nil {
+		if err := validateMultiplierParameters(uint64(n), mod, nil); err != nil {
+			return nil, err
+		}
+		return NewMultiplier(n, mod), nil
+	}
+	root, err := decodeJSONInt(rawRoot)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMultiplierParameters(uint64(n), mod, root); err != nil {
+		return nil, err
+	}
+	return NewMultiplierWithRoot(n, mod, root), nil
+}
+
+func quote(x *big.Int) json.RawMessage {
+	return json.RawMessage(strconv.Quote(x.String()))
+}
+
+// decodeJSONInt decodes an integer given as a JSON number or string.
+func decodeJSONInt(raw json.RawMessage) (*big.Int, error) {
+	var s string
+	if len(raw) > 0 && raw[0] == '"' {
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+	} else {
+		s = string(raw)
+	}
+	return parseInt(s)
+}
+
+// parseInt parses a decimal, or 0x-prefixed hexadecimal, integer.
+func parseInt(s string) (*big.Int, error) {
+	digits := strings.TrimPrefix(s, "-")
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		base = 16
+		digits = digits[2:]
+	}
+	x, ok := new(big.Int).SetString(digits, base)
+	if !ok || digits == "" || digits[0] == '+' || digits[0] == '-' {
+		return nil, fmt.Errorf("%w: integer %q", ErrInvalidEncoding, s)
+	}
+	if strings.HasPrefix(s, "-") {
+		x.Neg(x)
+	}
+	return x, nil
+}
+
+// textFields returns the space-separated fields between brackets.
+func textFields(text []byte) ([]string, error) {
+	s := strings.TrimSpace(string(text))
+	if !strings.HasPrefix(s, "[") || !strings.HasSuffix(s, "]") {
+		return nil, fmt.Errorf("%w: expected brackets", ErrInvalidEncoding)
+	}
+	return strings.Fields(s[1 : len(s)-1]), nil
+}
@@ -0,0 +1,143 @@
+package negacyclic_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"negacyclic"
+)
+
+func TestJSON(t *testing.T) {
+	t.Run("polynomial", testJSONPolynomial)
+	t.Run("vector", testJSONVector)
+	t.Run("multiplier", testJSONMultiplier)
+	t.Run("crt_multiplier", testJSONCRTMultiplier)
+	t.Run("invalid", testJSONInvalid)
+}
+
+func testJSONPolynomial(t *testing.T) {
+	bound := new(big.Int).Lsh(big.NewInt(1), 100)
+	p := randomElement(16, bound)
+	p.Coeffs[3].Neg(p.Coeffs[3])
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(negacyclic.Polynomial)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	checkPolynomialsEqual(t, p, got)
+
+	// Hexadecimal strings and plain numbers are accepted.
+	if err := json.Unmarshal([]byte(`["0x1f", "-0xA", 7, "-3"]`), got); err != nil {
+		t.Fatal(err)
+	}
+	checkPolynomialsEqual(t, polynomialFromInts(31, -10, 7, -3), got)
+
+	// The text encoding parses back, as does the output of String.
+	text, err := p.MarshalText()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{string(text), p.String()} {
+		got = new(negacyclic.Polynomial)
+		if err := got.UnmarshalText([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+		checkPolynomialsEqual(t, p, got)
+	}
+}
+
+func testJSONVector(t *testing.T) {
+	v := negacyclic.VectorFromSlice([]int{1, -1, 0, 5})
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[1,-1,0,5]" {
+		t.Fatalf("unexpected encoding %s", data)
+	}
+	got := new(negacyclic.Vector)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	checkVectorsEqual(t, v, got)
+	text, _ := v.MarshalText()
+	if string(text) != "[1 -1 0 5]" {
+		t.Fatalf("unexpected text %s", text)
+	}
+	got = new(negacyclic.Vector)
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatal(err)
+	}
+	checkVectorsEqual(t, v, got)
+}
+
+func testJSONMultiplier(t *testing.T) {
+	n := 512
+	q := negacyclic.RLWEPrime(40, 2*n)
+	mul := negacyclic.NewMultiplier(n, q)
+	data, err := json.Marshal(mul)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(negacyclic.Multiplier)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.N != n || got.Mod.Cmp(q) != 0 || got.Root().Cmp(mul.Root()) != 0 {
+		t.Fatal("parameters differ")
+	}
+	// Without the root, the default one is chosen.
+	got = new(negacyclic.Multiplier)
+	if err := json.Unmarshal([]byte(`{"n":512,"modulus":"`+q.String()+`"}`), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Root().Cmp(mul.Root()) != 0 {
+		t.Fatal("unexpected default root")
+	}
+}
+
+func testJSONCRTMultiplier(t *testing.T) {
+	n := 256
+	p, q := negacyclic.RLWEPrime(30, 2*n), negacyclic.RLWEPrime(40, 2*n)
+	mul := negacyclic.NewCRTMultiplier(n, p, q)
+	data, err := json.Marshal(mul)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(negacyclic.CRTMultiplier)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	if got.N != n || got.PQ.Cmp(mul.PQ) != 0 {
+		t.Fatal("parameters differ")
+	}
+	x, y := randomElement(n, mul.PQ), randomElement(n, mul.PQ)
+	checkPolynomialsEqual(t, mul.Mul(x, y), got.Mul(x, y))
+}
+
+func testJSONInvalid(t *testing.T) {
+	cases := map[string]struct {
+		target interface{}
+		data   string
+	}{
+		"degree":        {new(negacyclic.Polynomial), `["1", "2", "3"]`},
+		"float":         {new(negacyclic.Polynomial), `[1.5, 2]`},
+		"hex_garbage":   {new(negacyclic.Polynomial), `["0xz1", "2"]`},
+		"double_sign":   {new(negacyclic.Polynomial), `["--1", "2"]`},
+		"not_prime":     {new(negacyclic.Multiplier), `{"n": 16, "modulus": "99"}`},
+		"bad_root":      {new(negacyclic.Multiplier), `{"n": 16, "modulus": "97", "root": "2"}`},
+		"missing_mod":   {new(negacyclic.Multiplier), `{"n": 16}`},
+		"crt_one_prime": {new(negacyclic.CRTMultiplier), `{"n": 16, "moduli": ["97"]}`},
+		"crt_equal":     {new(negacyclic.CRTMultiplier), `{"n": 16, "moduli": ["97", "97"]}`},
+	}
+	for name, c := range cases {
+		if err := json.Unmarshal([]byte(c.data), c.target); !errors.Is(err, negacyclic.ErrInvalidEncoding) {
+			t.Errorf("%s: expected an encoding error, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package negacyclic
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// ErrInvalidEncoding is returned, possibly wrapped, when decoding malformed
+// binary data.
+var ErrInvalidEncoding = errors.New("invalid encoding")
+
+// encodingVersion is the first byte of every binary encoding of the package.
+const encodingVersion = 1
+
+// Formats of the binary encoding of polynomials.
+const (
+	// formatVariable encodes each coefficient as a sign byte, followed by
+	// the length-prefixed big-endian absolute value.
+	formatVariable = 0
+	// formatModulus encodes the modulus q, followed by the coefficients
+	// reduced modulo q in [0, q), big-endian on the byte length of q.
+	formatModulus = 1
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler. Coefficients are encoded
+// with their sign and a length prefix, so that any polynomial is supported.
+func (p *Polynomial) MarshalBinary() ([]byte, error) {
+	data := []byte{encodingVersion, formatVariable}
+	data = appendUvarint(data, uint64(p.Deg()))
+	for _, coeff := range p.Coeffs {
+		data = appendInt(data, coeff)
+	}
+	return data, nil
+}
+
+// MarshalBinaryMod returns a binary encoding of p modulo q, with all the
+// coefficients reduced in [0, q) and written on the byte length of q. It is
+// decoded by UnmarshalBinary.
+func (p *Polynomial) MarshalBinaryMod(q *big.Int) ([]byte, error) {
+	if q.Sign() <= 0 {
+		return nil, errors.New("modulus must be positive")
+	}
+	data := []byte{encodingVersion, formatModulus}
+	data = appendUvarint(data, uint64(p.Deg()))
+	data = appendInt(data, q)
+	width := (q.BitLen() + 7) / 8
+	coeff := new(big.Int)
+	for _, c := range p.Coeffs {
+		data = append(data, coeff.Mod(c, q).FillBytes(make([]byte, width))...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It decodes the
+// outputs of both MarshalBinary and MarshalBinaryMod, and rejects
+// non-canonical encodings, degrees that are not powers of two, coefficients
+// out of [0, q) for a modulus q, and trailing data.
+func (p *Polynomial) UnmarshalBinary(data []byte) error {
+	data, err := readVersion(data)
+	if err != nil {
+		return err
+	}
+	if len(data) == 0 {
+		return fmt.Errorf("%w: missing format", ErrInvalidEncoding)
+	}
+	format := data[0]
+	n, data, err := readUvarint(data[1:])
+	if err != nil {
+		return err
+	}
+	if !isPowerOfTwo(int(n)) || n > uint64(len(data)) {
+		return fmt.Errorf("%w: degree %d", ErrInvalidEncoding, n)
+	}
+	coeffs := make([]*big.Int, n)
+	switch format {
+	case formatVariable:
+		for i := range coeffs {
+			if coeffs[i], data, err = readInt(data); err != nil {
+				return err
+			}
+		}
+	case formatModulus:
+		var q *big.Int
+		if q, data, err = readInt(data); err != nil {
+			return err
+		}
+		if q.Sign() <= 0 {
+			return fmt.Errorf("%w: non-positive modulus", ErrInvalidEncoding)
+		}
+		width := (q.BitLen() + 7) / 8
+		if uint64(len(data)) < n*uint64(width) {
+			return fmt.Errorf("%w: truncated data", ErrInvalidEncoding)
+		}
+		for i := range coeffs {
+			coeffs[i] = new(big.Int).SetBytes(data[:width])
+			if coeffs[i].Cmp(q) >= 0 {
+				return fmt.Errorf("%w: coefficient %d out of range", ErrInvalidEncoding, i)
+			}
+			data = data[width:]
+		}
+	default:
+		return fmt.Errorf("%w: unknown format %d", ErrInvalidEncoding, format)
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: trailing data", ErrInvalidEncoding)
+	}
+	p.Coeffs = coeffs
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. Coefficients are encoded
+// as zig-zag varints.
+func (v *Vector) MarshalBinary() ([]byte, error) {
+	data := []byte{encodingVersion}
+	data = appendUvarint(data, uint64(v.Len()))
+	buf := make([]byte, binary.MaxVarintLen64)
+	for _, x := range v.Coeffs {
+		k := binary.PutVarint(buf, int64(x))
+		data = append(data, buf[:k]...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It rejects
+// non-canonical varints, coefficients that do not fit in an int, and trailing
+// data.
+func (v *Vector) UnmarshalBinary(data []byte) error {
+	data, err := readVersion(data)
+	if err != nil {
+		return err
+	}
+	n, data, err := readUvarint(data)
+	if err != nil {
+		return err
+	}
+	if n > uint64(len(data)) {
+		return fmt.Errorf("%w: length %d", ErrInvalidEncoding, n)
+	}
+	coeffs := make([]int, n)
+	for i := range coeffs {
+		x, k := binary.Varint(data)
+		if k <= 0 || k != varintLen(x) {
+			return fmt.Errorf("%w: coefficient %d", ErrInvalidEncoding, i)
+		}
+		if int64(int(x)) != x {
+			return fmt.Errorf("%w: coefficient %d overflows int", ErrInvalidEncoding, i)
+		}
+		coeffs[i] = int(x)
+		data = data[k:]
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: trailing data", ErrInvalidEncoding)
+	}
+	v.Coeffs = coeffs
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It encodes N, the
+// modulus and the primitive root of unity, from which UnmarshalBinary
+// rebuilds the same multiplier.
+func (mul *Multiplier) MarshalBinary() ([]byte, error) {
+	data := []byte{encodingVersion}
+	data = appendUvarint(data, uint64(mul.N))
+	data = appendInt(data, mul.Mod)
+	data = appendInt(data, mul.Root())
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It checks the
+// parameters as NewMultiplierWithRoot does, but returns an error instead of
+// panicking.
+func (mul *Multiplier) UnmarshalBinary(data []byte) error {
+	data, err := readVersion(data)
+	if err != nil {
+		return err
+	}
+	n, data, err := readUvarint(data)
+	if err != nil {
+		return err
+	}
+	mod, data, err := readInt(data)
+	if err != nil {
+		return err
+	}
+	root, data, err := readInt(data)
+	if err != nil {
+		return err
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: trailing data", ErrInvalidEncoding)
+	}
+	if err := validateMultiplierParameters(n, mod, root); err != nil {
+		return err
+	}
+	*mul = *newMultiplier(int(n), mod, root)
+	return nil
+}
+
+// Root returns the primitive 2N-th root of unity ψ used by the NTT.
+func (mul *Multiplier) Root() *big.Int {
+	return new(big.Int).Set(mul.root)
+}
+
+//
+// Internal
+//
+
+// maxEncodedDegree bounds the degree accepted when decoding parameters, which
+// are not backed by as many bytes of data.
+const maxEncodedDegree = 1 << 24
+
+// validateMultiplierParameters returns an error where NewMultiplierWithRoot
+// would panic. A nil root is not checked.
+func validateMultiplierParameters(n uint64, mod, root *big.Int) error {
+	if n > maxEncodedDegree || !isPowerOfTwo(int(n)) {
+		return fmt.Errorf("%w: degree %d", ErrInvalidEncoding, n)
+	}
+	if mod.Sign() <= 0 || !mod.ProbablyPrime(32) {
+		return fmt.Errorf("%w: modulus is not prime", ErrInvalidEncoding)
+	}
+	if new(big.Int).Mod(mod, big.NewInt(int64(2*n))).Cmp(big.NewInt(1)) != 0 {
+		return fmt.Errorf("%w: q != 1 mod 2n", ErrInvalidEncoding)
+	}
+	if root == nil {
+		return nil
+	}
+	minusOne := new(big.Int).Sub(mod, big.NewInt(1))
+	if root.Sign() < 0 || root.Cmp(mod) >= 0 ||
+		new(big.Int).Exp(root, big.NewInt(int64(n)), mod).Cmp(minusOne) != 0 {
+		return fmt.Errorf("%w: root is not a primitive 2n-th root of unity", ErrInvalidEncoding)
+	}
+	return nil
+}
+
+func readVersion(data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: empty data", ErrInvalidEncoding)
+	}
+	if data[0] != encodingVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidEncoding, data[0])
+	}
+	return data[1:], nil
+}
+
+func appendUvarint(data []byte, x uint64) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return append(data, buf[:binary.PutUvarint(buf, x)]...)
+}
+
+// readUvarint reads a minimally encoded uvarint.
+func readUvarint(data []byte) (uint64, []byte, error) {
+	x, k := binary.Uvarint(data)
+	if k <= 0 || k != len(appendUvarint(nil, x)) {
+		return 0, nil, fmt.Errorf("%w: malformed length", ErrInvalidEncoding)
+	}
+	return x, data[k:], nil
+}
+
+func varintLen(x int64) int {
+	buf := make([]byte, binary.MaxVarintLen64)
+	return binary.PutVarint(buf, x)
+}
+
+// appendInt appends a sign byte, 0 or 1, and the length-prefixed big-endian
+// absolute value of x.
+func appendInt(data []byte, x *big.Int) []byte {
+	sign := byte(0)
+	if x.Sign() < 0 {
+		sign = 1
+	}
+	abs := x.Bytes()
+	data = append(data, sign)
+	data = appendUvarint(data, uint64(len(abs)))
+	return append(data, abs...)
+}
+
+// readInt reads an integer written by appendInt, and rejects leading zero
+// bytes and negative zero.
+func readInt(data []byte) (*big.Int, []byte, error) {
+	if len(data) == 0 || data[0] > 1 {
+		return nil, nil, fmt.Errorf("%w: malformed sign", ErrInvalidEncoding)
+	}
+	neg := data[0] == 1
+	length, data, err := readUvarint(data[1:])
+	if err != nil {
+		return nil, nil, err
+	}
+	if length > uint64(len(data)) {
+		return nil, nil, fmt.Errorf("%w: truncated integer", ErrInvalidEncoding)
+	}
+	abs := data[:length]
+	if (length > 0 && abs[0] == 0) || (length == 0 && neg) {
+		return nil, nil, fmt.Errorf("%w: non-canonical integer", ErrInvalidEncoding)
+	}
+	x := new(big.Int).SetBytes(abs)
+	if neg {
+		x.Neg(x)
+	}
+	return x, data[length:], nil
+}
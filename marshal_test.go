@@ -0,0 +1,125 @@
+package negacyclic_test
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"negacyclic"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	t.Run("polynomial", testMarshalPolynomial)
+	t.Run("polynomial_mod", testMarshalPolynomialMod)
+	t.Run("vector", testMarshalVector)
+	t.Run("multiplier", testMarshalMultiplier)
+	t.Run("invalid", testUnmarshalInvalid)
+}
+
+func testMarshalPolynomial(t *testing.T) {
+	n := 64
+	bound := new(big.Int).Lsh(big.NewInt(1), 200)
+	p := randomElement(n, bound)
+	p.Coeffs[0].SetInt64(0)
+	p.Coeffs[1].Neg(p.Coeffs[1])
+	data, err := p.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(negacyclic.Polynomial)
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	checkPolynomialsEqual(t, p, got)
+}
+
+func testMarshalPolynomialMod(t *testing.T) {
+	n := 256
+	q := negacyclic.RLWEPrime(60, 2*n)
+	p := smallElement(n, 1000)
+	data, err := p.MarshalBinaryMod(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Header, then N coefficients on 8 bytes.
+	if len(data) > 16+8*n {
+		t.Fatalf("unexpected length %d", len(data))
+	}
+	got := new(negacyclic.Polynomial)
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	checkPolynomialsEqual(t, p.Copy().Mod(q), got.Mod(q))
+	// A coefficient equal to q is rejected.
+	last := data[len(data)-8:]
+	q.FillBytes(last)
+	if err := got.UnmarshalBinary(data); !errors.Is(err, negacyclic.ErrInvalidEncoding) {
+		t.Fatalf("expected an encoding error, got %v", err)
+	}
+}
+
+func testMarshalVector(t *testing.T) {
+	v := negacyclic.VectorFromSlice([]int{0, 1, -1, math.MaxInt, math.MinInt, 12345})
+	data, err := v.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(negacyclic.Vector)
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	checkVectorsEqual(t, v, got)
+}
+
+func testMarshalMultiplier(t *testing.T) {
+	n := 256
+	q := negacyclic.RLWEPrime(60, 2*n)
+	mul := negacyclic.NewMultiplier(n, q)
+	data, err := mul.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(negacyclic.Multiplier)
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got.N != n || got.Mod.Cmp(q) != 0 || got.Root().Cmp(mul.Root()) != 0 {
+		t.Fatal("parameters differ")
+	}
+	a := randomElement(n, q)
+	b := a.Copy()
+	mul.NTT(a)
+	got.NTT(b)
+	checkPolynomialsEqual(t, a, b)
+}
+
+func testUnmarshalInvalid(t *testing.T) {
+	p := negacyclic.NewPolynomial(2)
+	p.Coeffs[0].SetInt64(-5)
+	valid, _ := p.MarshalBinary()
+	mulData, _ := negacyclic.NewMultiplier(16, big.NewInt(97)).MarshalBinary()
+	cases := map[string]struct {
+		target interface{ UnmarshalBinary([]byte) error }
+		data   []byte
+	}{
+		"empty":             {new(negacyclic.Polynomial), nil},
+		"version":           {new(negacyclic.Polynomial), append([]byte{2}, valid[1:]...)},
+		"format":            {new(negacyclic.Polynomial), []byte{1, 7, 2, 0, 0, 0, 0}},
+		"degree":            {new(negacyclic.Polynomial), []byte{1, 0, 3, 0, 0, 0, 0, 0, 0}},
+		"overlong_length":   {new(negacyclic.Polynomial), []byte{1, 0, 0x82, 0, 0, 0, 0, 0, 0}},
+		"negative_zero":     {new(negacyclic.Polynomial), []byte{1, 0, 2, 1, 0, 0, 0}},
+		"leading_zero":      {new(negacyclic.Polynomial), []byte{1, 0, 2, 0, 1, 0, 0, 0}},
+		"truncated":         {new(negacyclic.Polynomial), valid[:len(valid)-1]},
+		"trailing":          {new(negacyclic.Polynomial), append(valid, 0)},
+		"vector_trailing":   {new(negacyclic.Vector), []byte{1, 1, 2, 0}},
+		"vector_overlong":   {new(negacyclic.Vector), []byte{1, 1, 0x80, 0}},
+		"multiplier_root":   {new(negacyclic.Multiplier), append(mulData[:len(mulData)-1], 1)},
+		"multiplier_degree": {new(negacyclic.Multiplier), append([]byte{1, 64}, mulData[2:]...)},
+	}
+	for name, c := range cases {
+		if err := c.target.UnmarshalBinary(c.data); !errors.Is(err, negacyclic.ErrInvalidEncoding) {
+			t.Errorf("%s: expected an encoding error, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package mldsa implements the ring utilities of ML-DSA, as specified in FIPS
+// 204: the NTT over Z_q[X]/(X^256+1) with q = 8380417, the rounding functions
+// Power2Round, Decompose, HighBits, LowBits, MakeHint and UseHint, and the
+// sampling of challenge polynomials.
+//
+// Since q = 1 mod 512, the ring is handled by a negacyclic.Multiplier, whose
+// NTT matches the standard one for the root of unity ζ = 1753. The rounding
+// functions act on vectors of small integers, with inputs in [0, q).
+package mldsa
+
+import (
+	"io"
+	"math/big"
+
+	"negacyclic"
+)
+
+const (
+	// Q is the modulus of ML-DSA.
+	Q = 8380417
+	// N is the degree of the ring.
+	N = 256
+	// D is the number of bits dropped from t by Power2Round.
+	D = 13
+	// Root is the primitive 512-th root of unity ζ of the standard NTT.
+	Root = 1753
+)
+
+// NewMultiplier returns a Multiplier for N and Q whose NTT representation is
+// the one of FIPS 204: index i holds the evaluation at ζ^{2·BitRev8(i)+1}.
+func NewMultiplier() *negacyclic.Multiplier {
+	return negacyclic.NewMultiplierWithRoot(N, big.NewInt(Q), big.NewInt(Root))
+}
+
+// Power2Round returns (r1, r0) such that r = r1·2^D + r0 mod q, with r0 in
+// (-2^{D-1}, 2^{D-1}], following Algorithm 35 of FIPS 204.
+func Power2Round(r int) (int, int) {
+	r = mod(r, Q)
+	r0 := centeredMod(r, 1<<D)
+	return (r - r0) >> D, r0
+}
+
+// Decompose returns (r1, r0) such that r = r1·2γ2 + r0 mod q, with r0 in
+// (-γ2, γ2], except when r - r0 = q - 1, in which case r1 = 0 and r0 is
+// decremented, following Algorithm 36 of FIPS 204. The parameter γ2 divides
+// (q-1)/2.
+func Decompose(r, gamma2 int) (int, int) {
+	r = mod(r, Q)
+	r0 := centeredMod(r, 2*gamma2)
+	if r-r0 == Q-1 {
+		return 0, r0 - 1
+	}
+	return (r - r0) / (2 * gamma2), r0
+}
+
+// HighBits returns r1 from Decompose(r, γ2).
+func HighBits(r, gamma2 int) int {
+	r1, _ := Decompose(r, gamma2)
+	return r1
+}
+
+// LowBits returns r0 from Decompose(r, γ2).
+func LowBits(r, gamma2 int) int {
+	_, r0 := Decompose(r, gamma2)
+	return r0
+}
+
+// MakeHint returns whether adding z to r alters the high bits of r.
+func MakeHint(z, r, gamma2 int) bool {
+	return HighBits(r, gamma2) != HighBits(r+z, gamma2)
+}
+
+// UseHint returns the high bits of r adjusted according to the hint h,
+// following Algorithm 40 of FIPS 204.
+func UseHint(h bool, r, gamma2 int) int {
+	m := (Q - 1) / (2 * gamma2)
+	r1, r0 := Decompose(r, gamma2)
+	if !h {
+		return r1
+	}
+	if r0 > 0 {
+		return (r1 + 1) % m
+	}
+	return mod(r1-1, m)
+}
+
+// Power2RoundVector applies Power2Round to each coefficient of v.
+func Power2RoundVector(v *negacyclic.Vector) (*negacyclic.Vector, *negacyclic.Vector) {
+	v1, v0 := negacyclic.NewVector(v.Len()), negacyclic.NewVector(v.Len())
+	for i, r := range v.Coeffs {
+		v1.Coeffs[i], v0.Coeffs[i] = Power2Round(r)
+	}
+	return v1, v0
+}
+
+// DecomposeVector applies Decompose to each coefficient of v.
+func DecomposeVector(v *negacyclic.Vector, gamma2 int) (*negacyclic.Vector, *negacyclic.Vector) {
+	v1, v0 := negacyclic.NewVector(v.Len()), negacyclic.NewVector(v.Len())
+	for i, r := range v.Coeffs {
+		v1.Coeffs[i], v0.Coeffs[i] = Decompose(r, gamma2)
+	}
+	return v1, v0
+}
+
+// HighBitsVector applies HighBits to each coefficient of v.
+func HighBitsVector(v *negacyclic.Vector, gamma2 int) *negacyclic.Vector {
+	v1, _ := DecomposeVector(v, gamma2)
+	return v1
+}
+
+// LowBitsVector applies LowBits to each coefficient of v.
+func LowBitsVector(v *negacyclic.Vector, gamma2 int) *negacyclic.Vector {
+	_, v0 := DecomposeVector(v, gamma2)
+	return v0
+}
+
+// MakeHintVector returns the hint vector of MakeHint, with coefficients in
+// {0, 1}, and its number of ones.
+func MakeHintVector(z, r *negacyclic.Vector, gamma2 int) (*negacyclic.Vector, int) {
+	if z.Len() != r.Len() {
+		panic("vectors of different lengths")
+	}
+	h := negacyclic.NewVector(r.Len())
+	ones := 0
+	for i := range h.Coeffs {
+		if MakeHint(z.Coeffs[i], r.Coeffs[i], gamma2) {
+			h.Coeffs[i] = 1
+			ones++
+		}
+	}
+	return h, ones
+}
+
+// UseHintVector applies UseHint to each coefficient of r, with the hint
+// vector h of coefficients in {0, 1}.
+func UseHintVector(h, r *negacyclic.Vector, gamma2 int) *negacyclic.Vector {
+	if h.Len() != r.Len() {
+		panic("vectors of different lengths")
+	}
+	res := negacyclic.NewVector(r.Len())
+	for i := range res.Coeffs {
+		res.Coeffs[i] = UseHint(h.Coeffs[i] != 0, r.Coeffs[i], gamma2)
+	}
+	return res
+}
+
+// SampleInBall returns a polynomial with τ coefficients in {-1, 1} and the
+// others zero, following Algorithm 29 of FIPS 204. The stream provides the
+// random bytes, the standard uses SHAKE256 of the commitment hash.
+func SampleInBall(tau int, stream io.Reader) *negacyclic.Vector {
+	if tau < 1 || tau > 64 {
+		panic("SampleInBall expects 1 <= τ <= 64")
+	}
+	c := negacyclic.NewVector(N)
+	buf := make([]byte, 8)
+	if _, err := io.ReadFull(stream, buf); err != nil {
+		panic(err)
+	}
+	var signs uint64
+	for i, b := range buf {
+		signs |= uint64(b) << (8 * i)
+	}
+	for i := N - tau; i < N; i++ {
+		// Rejection sampling of j in [0, i].
+		j := i + 1
+		for j > i {
+			if _, err := io.ReadFull(stream, buf[:1]); err != nil {
+				panic(err)
+			}
+			j = int(buf[0])
+		}
+		c.Coeffs[i] = c.Coeffs[j]
+		c.Coeffs[j] = 1 - 2*int(signs&1)
+		signs >>= 1
+	}
+	return c
+}
+
+//
+// Internal
+//
+
+// mod returns x mod m in [0, m).
+func mod(x, m int) int {
+	x %= m
+	if x < 0 {
+		x += m
+	}
+	return x
+}
+
+// centeredMod returns x mod m in (-m/2, m/2], for an even m.
+func centeredMod(x, m int) int {
+	x = mod(x, m)
+	if x > m/2 {
+		x -= m
+	}
+	return x
+}
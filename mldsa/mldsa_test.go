@@ -0,0 +1,271 @@
+package mldsa_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"math/rand"
+	"os"
+	"testing"
+
+	"negacyclic"
+	"negacyclic/mldsa"
+)
+
+// Parameters of ML-DSA-44.
+const (
+	k, l   = 4, 4
+	tau    = 39
+	gamma1 = 1 << 17
+	gamma2 = (mldsa.Q - 1) / 88
+	omega  = 80
+)
+
+func TestNTTOrdering(t *testing.T) {
+	mul := mldsa.NewMultiplier()
+	q := big.NewInt(mldsa.Q)
+	p := negacyclic.NewPolynomial(mldsa.N)
+	for i := range p.Coeffs {
+		p.Coeffs[i].SetInt64(rand.Int63n(mldsa.Q))
+	}
+	ntt := p.Copy()
+	mul.NTT(ntt)
+	for i := 0; i < mldsa.N; i++ {
+		rev := 0
+		for b := 0; b < 8; b++ {
+			rev |= (i >> b & 1) << (7 - b)
+		}
+		// ŵ_i = w(ζ^{2·BitRev8(i)+1}).
+		x := new(big.Int).Exp(big.NewInt(mldsa.Root), big.NewInt(int64(2*rev+1)), q)
+		eval, pow := new(big.Int), big.NewInt(1)
+		for _, coeff := range p.Coeffs {
+			eval.Add(eval, new(big.Int).Mul(coeff, pow))
+			pow.Mul(pow, x).Mod(pow, q)
+		}
+		if eval.Mod(eval, q).Cmp(ntt.Coeffs[i]) != 0 {
+			t.Fatalf("index %d: expected %d, got %d", i, eval, ntt.Coeffs[i])
+		}
+	}
+}
+
+func TestRounding(t *testing.T) {
+	t.Run("power2round", testPower2Round)
+	t.Run("decompose", testDecompose)
+	t.Run("hints", testHints)
+}
+
+func testPower2Round(t *testing.T) {
+	for i := 0; i < 10000; i++ {
+		r := rand.Intn(mldsa.Q)
+		r1, r0 := mldsa.Power2Round(r)
+		if r0 <= -(1<<(mldsa.D-1)) || r0 > 1<<(mldsa.D-1) {
+			t.Fatalf("r0 = %d out of range", r0)
+		}
+		if r1<<mldsa.D+r0 != r {
+			t.Fatalf("%d != %d·2^d + %d", r, r1, r0)
+		}
+	}
+}
+
+func testDecompose(t *testing.T) {
+	for _, g := range []int{(mldsa.Q - 1) / 88, (mldsa.Q - 1) / 32} {
+		m := (mldsa.Q - 1) / (2 * g)
+		values := []int{0, mldsa.Q - 1, mldsa.Q - 1 - g}
+		for i := 0; i < 10000; i++ {
+			values = append(values, rand.Intn(mldsa.Q))
+		}
+		for _, r := range values {
+			r1, r0 := mldsa.Decompose(r, g)
+			if r1 < 0 || r1 >= m {
+				t.Fatalf("r1 = %d out of range", r1)
+			}
+			if r0 < -g || r0 > g {
+				t.Fatalf("r0 = %d out of range", r0)
+			}
+			if (r1*2*g+r0-r)%mldsa.Q != 0 {
+				t.Fatalf("%d != %d·2γ2 + %d", r, r1, r0)
+			}
+			if mldsa.HighBits(r, g) != r1 || mldsa.LowBits(r, g) != r0 {
+				t.Fatal("HighBits and LowBits do not match Decompose")
+			}
+		}
+	}
+}
+
+func testHints(t *testing.T) {
+	for i := 0; i < 10000; i++ {
+		r := rand.Intn(mldsa.Q)
+		z := rand.Intn(2*gamma2+1) - gamma2
+		h := mldsa.MakeHint(z, r, gamma2)
+		if got, expected := mldsa.UseHint(h, r, gamma2), mldsa.HighBits(r+z, gamma2); got != expected {
+			t.Fatalf("r = %d, z = %d: UseHint returned %d instead of %d", r, z, got, expected)
+		}
+	}
+}
+
+func TestSampleInBall(t *testing.T) {
+	stream := make([]byte, 1024)
+	rand.Read(stream)
+	c := mldsa.SampleInBall(tau, bytes.NewReader(stream))
+	weight := 0
+	for _, x := range c.Coeffs {
+		switch x {
+		case 0:
+		case -1, 1:
+			weight++
+		default:
+			t.Fatalf("unexpected coefficient %d", x)
+		}
+	}
+	if weight != tau {
+		t.Fatalf("expected weight %d, got %d", tau, weight)
+	}
+}
+
+// testVector holds an ML-DSA-44 public key and deterministic signature, with
+// the intermediate values of key generation and verification: the matrix Â,
+// the secrets s1 and s2, the SHAKE256 output consumed by SampleInBall, the
+// challenge c, the response z, the hint h and the encoding of w1, see
+// testdata/README.
+type testVector struct {
+	PK     string  `json:"pk"`
+	Sig    string  `json:"signature"`
+	AHat   [][]int `json:"a_hat"`
+	S1     [][]int `json:"s1"`
+	S2     [][]int `json:"s2"`
+	Stream string  `json:"challenge_stream"`
+	C      []int   `json:"c"`
+	Z      [][]int `json:"z"`
+	H      [][]int `json:"h"`
+	W1     string  `json:"w1"`
+}
+
+// TestMLDSA44 recomputes t1 from the key generation samples and w1 from the
+// signature, and compares their encodings, as well as those of z and h, with
+// the fixtures.
+func TestMLDSA44(t *testing.T) {
+	data, err := os.ReadFile("testdata/mldsa44.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []testVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("no test vectors")
+	}
+	mul := mldsa.NewMultiplier()
+	for n, v := range vectors {
+		pk := decodeHex(t, v.PK)
+		sig := decodeHex(t, v.Sig)
+
+		// Signature: c~ || BitPack(z, γ1-1, γ1) || HintBitPack(h).
+		var packed []byte
+		for s := 0; s < l; s++ {
+			z := negacyclic.NewVector(mldsa.N)
+			for i, coeff := range v.Z[s] {
+				z.Coeffs[i] = gamma1 - coeff
+			}
+			packed = append(packed, negacyclic.PackVector(z, 18)...)
+		}
+		packed = append(packed, hintBitPack(v.H)...)
+		if !bytes.Equal(packed, sig[32:]) {
+			t.Fatalf("vector %d: z and h do not match the signature", n)
+		}
+
+		// Key generation: (t1, t0) = Power2Round(NTT^{-1}(Â∘NTT(s1)) + s2).
+		s1Hat := ntts(mul, v.S1)
+		for r := 0; r < k; r++ {
+			tr := negacyclic.NewPolynomial(mldsa.N)
+			for s := 0; s < l; s++ {
+				aHat := negacyclic.VectorFromSlice(v.AHat[r*l+s]).Polynomial()
+				tr = negacyclic.Add(tr, mul.Hadamard(aHat, s1Hat[s]))
+			}
+			mul.INTT(tr)
+			tr = negacyclic.Add(tr, negacyclic.VectorFromSlice(v.S2[r]))
+			t1, _ := mldsa.Power2RoundVector(vectorOf(tr))
+			if !bytes.Equal(negacyclic.PackVector(t1, 10), pk[32+320*r:32+320*(r+1)]) {
+				t.Fatalf("vector %d: t1[%d] does not match the public key", n, r)
+			}
+		}
+
+		// Challenge.
+		c := mldsa.SampleInBall(tau, bytes.NewReader(decodeHex(t, v.Stream)))
+		for i := range c.Coeffs {
+			if c.Coeffs[i] != v.C[i] {
+				t.Fatalf("vector %d: challenge coefficient %d: expected %d, got %d", n, i, v.C[i], c.Coeffs[i])
+			}
+		}
+
+		// Verification: w1 = UseHint(h, NTT^{-1}(Â∘NTT(z) - NTT(c)∘NTT(t1·2^d))).
+		zHat := ntts(mul, v.Z)
+		cHat := ntts(mul, [][]int{v.C})[0]
+		var w1 []byte
+		for r := 0; r < k; r++ {
+			w := negacyclic.NewPolynomial(mldsa.N)
+			for s := 0; s < l; s++ {
+				aHat := negacyclic.VectorFromSlice(v.AHat[r*l+s]).Polynomial()
+				w = negacyclic.Add(w, mul.Hadamard(aHat, zHat[s]))
+			}
+			t1 := negacyclic.UnpackVector(pk[32+320*r:32+320*(r+1)], mldsa.N, 10, false).Polynomial()
+			t1.Scale(big.NewInt(1 << mldsa.D))
+			mul.NTT(t1)
+			w = negacyclic.Sub(w, mul.Hadamard(cHat, t1))
+			mul.INTT(w)
+			h := negacyclic.VectorFromSlice(v.H[r])
+			w1 = append(w1, negacyclic.PackVector(mldsa.UseHintVector(h, vectorOf(w), gamma2), 6)...)
+		}
+		if !bytes.Equal(w1, decodeHex(t, v.W1)) {
+			t.Fatalf("vector %d: w1 mismatch", n)
+		}
+	}
+}
+
+// ntts returns the NTT representations of the small polynomials.
+func ntts(mul *negacyclic.Multiplier, polys [][]int) []*negacyclic.Polynomial {
+	q := big.NewInt(mldsa.Q)
+	res := make([]*negacyclic.Polynomial, len(polys))
+	for i, p := range polys {
+		res[i] = negacyclic.VectorFromSlice(p).Polynomial()
+		for _, coeff := range res[i].Coeffs {
+			coeff.Mod(coeff, q)
+		}
+		mul.NTT(res[i])
+	}
+	return res
+}
+
+// vectorOf returns the coefficients of p modulo q, in [0, q).
+func vectorOf(p *negacyclic.Polynomial) *negacyclic.Vector {
+	q := big.NewInt(mldsa.Q)
+	v := negacyclic.NewVector(p.Deg())
+	for i, coeff := range p.Coeffs {
+		v.Coeffs[i] = int(new(big.Int).Mod(coeff, q).Int64())
+	}
+	return v
+}
+
+func hintBitPack(h [][]int) []byte {
+	out := make([]byte, omega+k)
+	index := 0
+	for r, hr := range h {
+		for i, bit := range hr {
+			if bit != 0 {
+				out[index] = byte(i)
+				index++
+			}
+		}
+		out[omega+r] = byte(index)
+	}
+	return out
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
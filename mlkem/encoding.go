@@ -0,0 +1,79 @@
+package mlkem
+
+import "negacyclic"
+
+// Compress returns ⌈(2^d/q)·x⌋ mod 2^d, for 1 <= d < 12, following Section
+// 4.2.1 of FIPS 203.
+func Compress(x, d int) int {
+	checkBits(d)
+	// ⌊(2^d·x + ⌊q/2⌋)/q⌋ rounds half up, as the standard requires.
+	return ((mod(x)<<d + Q/2) / Q) & (1<<d - 1)
+}
+
+// Decompress returns ⌈(q/2^d)·y⌋, for 1 <= d < 12.
+func Decompress(y, d int) int {
+	checkBits(d)
+	return (y*Q + 1<<(d-1)) >> d
+}
+
+// CompressVector returns the coefficient-wise compression of f.
+func CompressVector(f *negacyclic.Vector, d int) *negacyclic.Vector {
+	res := negacyclic.NewVector(f.Len())
+	for i, x := range f.Coeffs {
+		res.Coeffs[i] = Compress(x, d)
+	}
+	return res
+}
+
+// DecompressVector returns the coefficient-wise decompression of f.
+func DecompressVector(f *negacyclic.Vector, d int) *negacyclic.Vector {
+	res := negacyclic.NewVector(f.Len())
+	for i, y := range f.Coeffs {
+		res.Coeffs[i] = Decompress(y, d)
+	}
+	return res
+}
+
+// ByteEncode serializes the N integers of f on d bits each, little-endian,
+// into 32·d bytes, following Algorithm 5 of FIPS 203. The coefficients are
+// taken modulo 2^d for d < 12, and modulo q for d = 12.
+func ByteEncode(f *negacyclic.Vector, d int) []byte {
+	checkLength(f)
+	if d < 1 || d > 12 {
+		panic("ByteEncode expects 1 <= d <= 12")
+	}
+	g := negacyclic.NewVector(N)
+	for i, a := range f.Coeffs {
+		if d == 12 {
+			g.Coeffs[i] = mod(a)
+		} else {
+			g.Coeffs[i] = a & (1<<d - 1)
+		}
+	}
+	return negacyclic.PackVector(g, d)
+}
+
+// ByteDecode deserializes 32·d bytes into N integers of d bits each,
+// following Algorithm 6 of FIPS 203. For d = 12, the integers are reduced
+// modulo q.
+func ByteDecode(b []byte, d int) *negacyclic.Vector {
+	if d < 1 || d > 12 {
+		panic("ByteDecode expects 1 <= d <= 12")
+	}
+	if len(b) != 32*d {
+		panic("ByteDecode expects 32·d bytes")
+	}
+	f := negacyclic.UnpackVector(b, N, d, false)
+	if d == 12 {
+		for i := range f.Coeffs {
+			f.Coeffs[i] %= Q
+		}
+	}
+	return f
+}
+
+func checkBits(d int) {
+	if d < 1 || d >= 12 {
+		panic("compression expects 1 <= d < 12")
+	}
+}
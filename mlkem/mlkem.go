@@ -0,0 +1,161 @@
+// Package mlkem implements the polynomial ring layer of ML-KEM, as specified
+// in FIPS 203: the ring Z_q[X]/(X^256+1) with q = 3329, its incomplete NTT,
+// compression and byte encoding.
+//
+// Since q - 1 = 2^8·13, Z_q only holds primitive 256-th roots of unity and
+// X^256+1 splits into 128 quadratic factors X^2 - ζ^{2BitRev7(i)+1}. The
+// negacyclic.Multiplier, which requires a primitive 512-th root of unity,
+// cannot be used with these parameters.
+//
+// Polynomials are vectors of N small integers. The functions expect
+// coefficients in [0, q) and return coefficients in [0, q), unless stated
+// otherwise.
+package mlkem
+
+import "negacyclic"
+
+const (
+	// Q is the modulus of ML-KEM.
+	Q = 3329
+	// N is the degree of the ring.
+	N = 256
+)
+
+// Zetas holds ζ^{BitRev7(i)} mod q, for ζ = 17 and i = 0, ..., 127, in the
+// order of FIPS 203, Appendix A.
+var Zetas = [128]int{
+	1, 1729, 2580, 3289, 2642, 630, 1897, 848, 1062, 1919, 193, 797, 2786, 3260, 569, 1746,
+	296, 2447, 1339, 1476, 3046, 56, 2240, 1333, 1426, 2094, 535, 2882, 2393, 2879, 1974, 821,
+	289, 331, 3253, 1756, 1197, 2304, 2277, 2055, 650, 1977, 2513, 632, 2865, 33, 1320, 1915,
+	2319, 1435, 807, 452, 1438, 2868, 1534, 2402, 2647, 2617, 1481, 648, 2474, 3110, 1227, 910,
+	17, 2761, 583, 2649, 1637, 723, 2288, 1100, 1409, 2662, 3281, 233, 756, 2156, 3015, 3050,
+	1703, 1651, 2789, 1789, 1847, 952, 1461, 2687, 939, 2308, 2437, 2388, 733, 2337, 268, 641,
+	1584, 2298, 2037, 3220, 375, 2549, 2090, 1645, 1063, 319, 2773, 757, 2099, 561, 2466, 2594,
+	2804, 1092, 403, 1026, 1143, 2150, 2775, 886, 1722, 1212, 1874, 1029, 2110, 2935, 885, 2154,
+}
+
+// Gammas holds ζ^{2BitRev7(i)+1} mod q, for i = 0, ..., 127, the roots of the
+// quadratic factors used by MultiplyNTTs, in the order of FIPS 203, Appendix
+// A.
+var Gammas = [128]int{
+	17, 3312, 2761, 568, 583, 2746, 2649, 680, 1637, 1692, 723, 2606, 2288, 1041, 1100, 2229,
+	1409, 1920, 2662, 667, 3281, 48, 233, 3096, 756, 2573, 2156, 1173, 3015, 314, 3050, 279,
+	1703, 1626, 1651, 1678, 2789, 540, 1789, 1540, 1847, 1482, 952, 2377, 1461, 1868, 2687, 642,
+	939, 2390, 2308, 1021, 2437, 892, 2388, 941, 733, 2596, 2337, 992, 268, 3061, 641, 2688,
+	1584, 1745, 2298, 1031, 2037, 1292, 3220, 109, 375, 2954, 2549, 780, 2090, 1239, 1645, 1684,
+	1063, 2266, 319, 3010, 2773, 556, 757, 2572, 2099, 1230, 561, 2768, 2466, 863, 2594, 735,
+	2804, 525, 1092, 2237, 403, 2926, 1026, 2303, 1143, 2186, 2150, 1179, 2775, 554, 886, 2443,
+	1722, 1607, 1212, 2117, 1874, 1455, 1029, 2300, 2110, 1219, 2935, 394, 885, 2444, 2154, 1175,
+}
+
+// NTT computes in place the NTT representation of f, following Algorithm 9 of
+// FIPS 203. The coefficients of f may be any integers, they are reduced
+// modulo q first.
+func NTT(f *negacyclic.Vector) {
+	checkLength(f)
+	a := f.Coeffs
+	for j := range a {
+		a[j] = mod(a[j])
+	}
+	i := 1
+	for length := 128; length >= 2; length /= 2 {
+		for start := 0; start < N; start += 2 * length {
+			zeta := Zetas[i]
+			i++
+			for j := start; j < start+length; j++ {
+				t := zeta * a[j+length] % Q
+				a[j+length] = mod(a[j] - t)
+				a[j] = (a[j] + t) % Q
+			}
+		}
+	}
+}
+
+// InverseNTT computes in place the polynomial whose NTT representation is f,
+// following Algorithm 10 of FIPS 203.
+func InverseNTT(f *negacyclic.Vector) {
+	checkLength(f)
+	a := f.Coeffs
+	for j := range a {
+		a[j] = mod(a[j])
+	}
+	i := 127
+	for length := 2; length <= 128; length *= 2 {
+		for start := 0; start < N; start += 2 * length {
+			zeta := Zetas[i]
+			i--
+			for j := start; j < start+length; j++ {
+				t := a[j]
+				a[j] = (t + a[j+length]) % Q
+				a[j+length] = zeta * mod(a[j+length]-t) % Q
+			}
+		}
+	}
+	// 3303 = 128^{-1} mod q.
+	for j := range a {
+		a[j] = a[j] * 3303 % Q
+	}
+}
+
+// MultiplyNTTs returns the NTT representation of the product of the
+// polynomials whose NTT representations are f and g, following Algorithm 11
+// of FIPS 203.
+func MultiplyNTTs(f, g *negacyclic.Vector) *negacyclic.Vector {
+	checkLength(f)
+	checkLength(g)
+	h := negacyclic.NewVector(N)
+	for i := 0; i < N/2; i++ {
+		h.Coeffs[2*i], h.Coeffs[2*i+1] = BaseCaseMultiply(
+			f.Coeffs[2*i], f.Coeffs[2*i+1], g.Coeffs[2*i], g.Coeffs[2*i+1], Gammas[i])
+	}
+	return h
+}
+
+// BaseCaseMultiply returns the product (a0 + a1·X)(b0 + b1·X) modulo
+// X^2 - γ, following Algorithm 12 of FIPS 203.
+func BaseCaseMultiply(a0, a1, b0, b1, gamma int) (int, int) {
+	c0 := (a0*b0 + a1*b1%Q*gamma) % Q
+	c1 := (a0*b1 + a1*b0) % Q
+	return c0, c1
+}
+
+// Add returns f + g mod q.
+func Add(f, g *negacyclic.Vector) *negacyclic.Vector {
+	checkLength(f)
+	checkLength(g)
+	h := negacyclic.NewVector(N)
+	for i := range h.Coeffs {
+		h.Coeffs[i] = mod(f.Coeffs[i] + g.Coeffs[i])
+	}
+	return h
+}
+
+// Sub returns f - g mod q.
+func Sub(f, g *negacyclic.Vector) *negacyclic.Vector {
+	checkLength(f)
+	checkLength(g)
+	h := negacyclic.NewVector(N)
+	for i := range h.Coeffs {
+		h.Coeffs[i] = mod(f.Coeffs[i] - g.Coeffs[i])
+	}
+	return h
+}
+
+//
+// Internal
+//
+
+func checkLength(f *negacyclic.Vector) {
+	if f.Len() != N {
+		panic("ML-KEM polynomials have 256 coefficients")
+	}
+}
+
+// mod returns x mod q in [0, q).
+func mod(x int) int {
+	x %= Q
+	if x < 0 {
+		x += Q
+	}
+	return x
+}
@@ -0,0 +1,250 @@
+package mlkem_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"testing"
+
+	"negacyclic"
+	"negacyclic/mlkem"
+)
+
+func TestTables(t *testing.T) {
+	for i := 0; i < 128; i++ {
+		rev := 0
+		for b := 0; b < 7; b++ {
+			rev |= (i >> b & 1) << (6 - b)
+		}
+		if zeta := power(17, rev); mlkem.Zetas[i] != zeta {
+			t.Fatalf("zeta %d: expected %d, got %d", i, zeta, mlkem.Zetas[i])
+		}
+		if gamma := power(17, 2*rev+1); mlkem.Gammas[i] != gamma {
+			t.Fatalf("gamma %d: expected %d, got %d", i, gamma, mlkem.Gammas[i])
+		}
+	}
+}
+
+func TestNTT(t *testing.T) {
+	t.Run("roundtrip", testNTTRoundtrip)
+	t.Run("multiplication", testNTTMultiplication)
+}
+
+func testNTTRoundtrip(t *testing.T) {
+	f := randomPolynomial()
+	g := negacyclic.VectorFromSlice(append([]int{}, f.Coeffs...))
+	mlkem.NTT(g)
+	mlkem.InverseNTT(g)
+	for i := range f.Coeffs {
+		if f.Coeffs[i] != g.Coeffs[i] {
+			t.Fatalf("coefficient %d: expected %d, got %d", i, f.Coeffs[i], g.Coeffs[i])
+		}
+	}
+}
+
+func testNTTMultiplication(t *testing.T) {
+	f, g := randomPolynomial(), randomPolynomial()
+	expected := schoolbook(f, g)
+	mlkem.NTT(f)
+	mlkem.NTT(g)
+	h := mlkem.MultiplyNTTs(f, g)
+	mlkem.InverseNTT(h)
+	for i := range h.Coeffs {
+		if h.Coeffs[i] != expected.Coeffs[i] {
+			t.Fatalf("coefficient %d: expected %d, got %d", i, expected.Coeffs[i], h.Coeffs[i])
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	for _, d := range []int{1, 4, 5, 10, 11} {
+		// The rounding error is at most ⌈q/2^{d+1}⌋.
+		bound := (mlkem.Q + 1<<d) >> (d + 1)
+		for x := 0; x < mlkem.Q; x++ {
+			y := mlkem.Compress(x, d)
+			if y < 0 || y >= 1<<d {
+				t.Fatalf("d = %d: Compress(%d) = %d out of range", d, x, y)
+			}
+			diff := (mlkem.Decompress(y, d) - x + mlkem.Q) % mlkem.Q
+			if diff > mlkem.Q/2 {
+				diff = mlkem.Q - diff
+			}
+			if diff > bound {
+				t.Fatalf("d = %d: error %d for %d exceeds %d", d, diff, x, bound)
+			}
+		}
+		for y := 0; y < 1<<d; y++ {
+			if got := mlkem.Compress(mlkem.Decompress(y, d), d); got != y {
+				t.Fatalf("d = %d: Compress(Decompress(%d)) = %d", d, y, got)
+			}
+		}
+	}
+}
+
+func TestByteEncode(t *testing.T) {
+	for d := 1; d <= 12; d++ {
+		f := negacyclic.NewVector(mlkem.N)
+		for i := range f.Coeffs {
+			if d == 12 {
+				f.Coeffs[i] = rand.Intn(mlkem.Q)
+			} else {
+				f.Coeffs[i] = rand.Intn(1 << d)
+			}
+		}
+		b := mlkem.ByteEncode(f, d)
+		if len(b) != 32*d {
+			t.Fatalf("d = %d: expected %d bytes, got %d", d, 32*d, len(b))
+		}
+		g := mlkem.ByteDecode(b, d)
+		for i := range f.Coeffs {
+			if f.Coeffs[i] != g.Coeffs[i] {
+				t.Fatalf("d = %d, coefficient %d: expected %d, got %d", d, i, f.Coeffs[i], g.Coeffs[i])
+			}
+		}
+	}
+}
+
+// testVector holds the intermediate values of ML-KEM-768 key generation and
+// encapsulation for a seed d || z and the message m, see testdata/README.
+type testVector struct {
+	EK   string   `json:"ek"`
+	M    string   `json:"m"`
+	C    string   `json:"c"`
+	AHat []string `json:"a_hat"`
+	S    [][]int  `json:"s"`
+	E    [][]int  `json:"e"`
+	Y    [][]int  `json:"y"`
+	E1   [][]int  `json:"e1"`
+	E2   []int    `json:"e2"`
+}
+
+// TestMLKEM768 recomputes K-PKE key generation, encryption and decryption of
+// ML-KEM-768 (k = 3, du = 10, dv = 4) from the sampled values, and compares
+// the encodings with the fixtures.
+func TestMLKEM768(t *testing.T) {
+	data, err := os.ReadFile("testdata/mlkem768.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []testVector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	if len(vectors) == 0 {
+		t.Fatal("no test vectors")
+	}
+	const k, du, dv = 3, 10, 4
+	for n, v := range vectors {
+		aHat := make([]*negacyclic.Vector, k*k)
+		for i, a := range v.AHat {
+			aHat[i] = mlkem.ByteDecode(decodeHex(t, a), 12)
+		}
+		ek := decodeHex(t, v.EK)
+
+		// Key generation: t̂ = Â∘ŝ + ê.
+		sHat := ntts(v.S)
+		eHat := ntts(v.E)
+		var ekPKE []byte
+		for i := 0; i < k; i++ {
+			tHat := eHat[i]
+			for j := 0; j < k; j++ {
+				tHat = mlkem.Add(tHat, mlkem.MultiplyNTTs(aHat[i*k+j], sHat[j]))
+			}
+			ekPKE = append(ekPKE, mlkem.ByteEncode(tHat, 12)...)
+		}
+		if !bytes.Equal(ekPKE, ek[:384*k]) {
+			t.Fatalf("vector %d: encapsulation key mismatch", n)
+		}
+
+		// Encryption: u = NTT^{-1}(Âᵀ∘ŷ) + e1, v = NTT^{-1}(t̂ᵀ∘ŷ) + e2 + μ.
+		yHat := ntts(v.Y)
+		m := decodeHex(t, v.M)
+		var c []byte
+		for i := 0; i < k; i++ {
+			u := negacyclic.NewVector(mlkem.N)
+			for j := 0; j < k; j++ {
+				u = mlkem.Add(u, mlkem.MultiplyNTTs(aHat[j*k+i], yHat[j]))
+			}
+			mlkem.InverseNTT(u)
+			u = mlkem.Add(u, negacyclic.VectorFromSlice(v.E1[i]))
+			c = append(c, mlkem.ByteEncode(mlkem.CompressVector(u, du), du)...)
+		}
+		w := negacyclic.NewVector(mlkem.N)
+		for j := 0; j < k; j++ {
+			tHat := mlkem.ByteDecode(ek[384*j:384*(j+1)], 12)
+			w = mlkem.Add(w, mlkem.MultiplyNTTs(tHat, yHat[j]))
+		}
+		mlkem.InverseNTT(w)
+		mu := mlkem.DecompressVector(mlkem.ByteDecode(m, 1), 1)
+		w = mlkem.Add(mlkem.Add(w, negacyclic.VectorFromSlice(v.E2)), mu)
+		c = append(c, mlkem.ByteEncode(mlkem.CompressVector(w, dv), dv)...)
+		expected := decodeHex(t, v.C)
+		if !bytes.Equal(c, expected) {
+			t.Fatalf("vector %d: ciphertext mismatch", n)
+		}
+
+		// Decryption: w = v' - NTT^{-1}(ŝᵀ∘NTT(u')).
+		w = mlkem.DecompressVector(mlkem.ByteDecode(expected[32*du*k:], dv), dv)
+		prod := negacyclic.NewVector(mlkem.N)
+		for i := 0; i < k; i++ {
+			u := mlkem.DecompressVector(mlkem.ByteDecode(expected[32*du*i:32*du*(i+1)], du), du)
+			mlkem.NTT(u)
+			prod = mlkem.Add(prod, mlkem.MultiplyNTTs(sHat[i], u))
+		}
+		mlkem.InverseNTT(prod)
+		got := mlkem.ByteEncode(mlkem.CompressVector(mlkem.Sub(w, prod), 1), 1)
+		if !bytes.Equal(got, m) {
+			t.Fatalf("vector %d: decrypted message mismatch", n)
+		}
+	}
+}
+
+func ntts(polys [][]int) []*negacyclic.Vector {
+	res := make([]*negacyclic.Vector, len(polys))
+	for i, p := range polys {
+		res[i] = negacyclic.VectorFromSlice(append([]int{}, p...))
+		mlkem.NTT(res[i])
+	}
+	return res
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func randomPolynomial() *negacyclic.Vector {
+	f := negacyclic.NewVector(mlkem.N)
+	for i := range f.Coeffs {
+		f.Coeffs[i] = rand.Intn(mlkem.Q)
+	}
+	return f
+}
+
+// schoolbook returns f·g mod (X^N+1, q).
+func schoolbook(f, g *negacyclic.Vector) *negacyclic.Vector {
+	h := negacyclic.NewVector(mlkem.N)
+	for i, a := range f.Coeffs {
+		for j, b := range g.Coeffs {
+			if i+j < mlkem.N {
+				h.Coeffs[i+j] = (h.Coeffs[i+j] + a*b) % mlkem.Q
+			} else {
+				h.Coeffs[i+j-mlkem.N] = (h.Coeffs[i+j-mlkem.N] - a*b%mlkem.Q + mlkem.Q) % mlkem.Q
+			}
+		}
+	}
+	return h
+}
+
+func power(x, e int) int {
+	res := 1
+	for ; e > 0; e-- {
+		res = res * x % mlkem.Q
+	}
+	return res
+}
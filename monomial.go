@@ -0,0 +1,101 @@
+package negacyclic
+
+import "math/big"
+
+// MulMonomial sets p to X^k·p in Z[X]/(X^N+1). Since X^N = -1, this is a
+// cyclic shift of the coefficients by k positions, where the coefficients
+// wrapping around are negated. The exponent k is taken modulo 2N.
+func (p *Polynomial) MulMonomial(k int) {
+	n := p.Deg()
+	k, negateAll := monomialExponent(k, n)
+	old := make([]*big.Int, n)
+	copy(old, p.Coeffs)
+	for i, coeff := range old {
+		index := i + k
+		wrap := index >= n
+		if wrap {
+			index -= n
+		}
+		if wrap != negateAll {
+			coeff.Neg(coeff)
+		}
+		p.Coeffs[index] = coeff
+	}
+}
+
+// MulMonomial returns X^k·p in Z[X]/(X^N+1), see Polynomial.MulMonomial.
+func MulMonomial(p *Polynomial, k int) *Polynomial {
+	res := p.Copy()
+	res.MulMonomial(k)
+	return res
+}
+
+// MulMonomial sets v to X^k·v in Z[X]/(X^N+1), see Polynomial.MulMonomial.
+func (v *Vector) MulMonomial(k int) {
+	n := v.Len()
+	k, negateAll := monomialExponent(k, n)
+	old := make([]int, n)
+	copy(old, v.Coeffs)
+	for i, coeff := range old {
+		index := i + k
+		wrap := index >= n
+		if wrap {
+			index -= n
+		}
+		if wrap != negateAll {
+			coeff = -coeff
+		}
+		v.Coeffs[index] = coeff
+	}
+}
+
+// MulMonomialVector returns X^k·v in Z[X]/(X^N+1), see
+// Polynomial.MulMonomial.
+func MulMonomialVector(v *Vector, k int) *Vector {
+	coeffs := make([]int, v.Len())
+	copy(coeffs, v.Coeffs)
+	res := VectorFromSlice(coeffs)
+	res.MulMonomial(k)
+	return res
+}
+
+// MulMonomialNTT sets a to the product of a and X^k, where a is given in the
+// NTT domain, that is, as returned by NTT. It multiplies each evaluation of a
+// by the corresponding evaluation of X^k: the i-th evaluation is at
+// ψ^{2·brv(i)+1}, where X^k takes the value w^{2·brv(i)+1} for w = ψ^k. The
+// exponent k is taken modulo 2N.
+func (mul *Multiplier) MulMonomialNTT(a *Polynomial, k int) {
+	if a.Deg() != mul.N {
+		panic("polynomial of unexpected degree")
+	}
+	k %= 2 * mul.N
+	if k < 0 {
+		k += 2 * mul.N
+	}
+	// odd[j] = w^{2j+1}.
+	w := new(big.Int).Exp(mul.root, big.NewInt(int64(k)), mul.Mod)
+	w2 := new(big.Int).Mul(w, w)
+	w2.Mod(w2, mul.Mod)
+	odd := make([]*big.Int, mul.N)
+	odd[0] = w
+	for j := 1; j < mul.N; j++ {
+		odd[j] = new(big.Int).Mul(odd[j-1], w2)
+		odd[j].Mod(odd[j], mul.Mod)
+	}
+	for i, coeff := range a.Coeffs {
+		coeff.Mul(coeff, odd[reverseBits(i, mul.N)]).Mod(coeff, mul.Mod)
+	}
+}
+
+// monomialExponent reduces k modulo 2N, and returns k mod N together with
+// whether X^k = -X^{k mod N}.
+func monomialExponent(k, n int) (int, bool) {
+	k %= 2 * n
+	if k < 0 {
+		k += 2 * n
+	}
+	if k >= n {
+		return k - n, true
+	}
+	return k, false
+}
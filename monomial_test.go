@@ -0,0 +1,109 @@
+package negacyclic_test
+
+import (
+	"math/big"
+	"testing"
+
+	"negacyclic"
+)
+
+func TestMonomialMultiplication(t *testing.T) {
+	t.Run("polynomial", testMulMonomial)
+	t.Run("polynomial_in_place", testMulMonomialInPlace)
+	t.Run("vector", testMulMonomialVector)
+	t.Run("ntt", testMulMonomialNTT)
+}
+
+var monomialExponents = []int{0, 1, 5, 63, 64, 65, 100, 127, 128, -1, -70, 1000}
+
+func testMulMonomial(t *testing.T) {
+	n := 64
+	p := smallElement(n, 1000)
+	for _, k := range monomialExponents {
+		got := negacyclic.MulMonomial(p, k)
+		checkPolynomialsEqual(t, negacyclic.Karatsuba(monomial(n, k), p), got)
+	}
+}
+
+func testMulMonomialInPlace(t *testing.T) {
+	n := 64
+	p := smallElement(n, 1000)
+	for _, k := range monomialExponents {
+		expected := negacyclic.Karatsuba(monomial(n, k), p)
+		p.MulMonomial(k)
+		checkPolynomialsEqual(t, expected, p)
+	}
+}
+
+func testMulMonomialVector(t *testing.T) {
+	n := 64
+	v := negacyclic.NewVector(n)
+	for i := range v.Coeffs {
+		v.Coeffs[i] = i - n/2
+	}
+	for _, k := range monomialExponents {
+		expected := negacyclic.Karatsuba(monomial(n, k), v.Polynomial())
+		got := negacyclic.MulMonomialVector(v, k)
+		checkPolynomialsEqual(t, expected, got.Polynomial())
+		v.MulMonomial(k)
+		checkPolynomialsEqual(t, expected, v.Polynomial())
+	}
+}
+
+func testMulMonomialNTT(t *testing.T) {
+	n := 256
+	q := big.NewInt(12289)
+	m := negacyclic.NewMultiplier(n, q)
+	for _, k := range monomialExponents {
+		p := randomElement(n, q)
+		expected := negacyclic.MulMonomial(p, k).Mod(q)
+		m.NTT(p)
+		m.MulMonomialNTT(p, k)
+		m.INTT(p)
+		checkPolynomialsEqual(t, expected, p.Mod(q))
+	}
+}
+
+// monomial returns X^k in Z[X]/(X^n+1).
+func monomial(n, k int) *negacyclic.Polynomial {
+	p := negacyclic.NewPolynomial(n)
+	k %= 2 * n
+	if k < 0 {
+		k += 2 * n
+	}
+	if k < n {
+		p.Coeffs[k].SetInt64(1)
+	} else {
+		p.Coeffs[k-n].SetInt64(-1)
+	}
+	return p
+}
+
+func checkPolynomialsEqual(t *testing.T, expected, got *negacyclic.Polynomial) {
+	t.Helper()
+	for i := range expected.Coeffs {
+		if expected.Coeffs[i].Cmp(got.Coeffs[i]) != 0 {
+			t.Fatalf("coefficient %d: expected %d, got %d", i, expected.Coeffs[i], got.Coeffs[i])
+		}
+	}
+}
+
+func TestAutomorphism(t *testing.T) {
+	n := 64
+	p := smallElement(n, 1000)
+	v := negacyclic.NewVector(n)
+	for i := range v.Coeffs {
+		v.Coeffs[i] = int(p.Coeffs[i].Int64())
+	}
+	for _, k := range []int{1, 3, 5, 2*n - 1, -1, 2*n + 5} {
+		// Evaluate p(X^k) as Σ p_i X^{ik} with monomial products.
+		expected := negacyclic.NewPolynomial(n)
+		for i, coeff := range p.Coeffs {
+			term := monomial(n, i*k)
+			term.Scale(coeff)
+			expected = negacyclic.Add(expected, term)
+		}
+		checkPolynomialsEqual(t, expected, p.Automorphism(k))
+		checkPolynomialsEqual(t, expected, v.Automorphism(k).Polynomial())
+	}
+}
@@ -10,31 +10,44 @@ type Multiplier struct {
 	nInvQ              *big.Int
 	rootsBitReverse    []*big.Int
 	invRootsBitReverse []*big.Int
+	// root is the primitive 2N-th root of unity ψ of the NTT.
+	root *big.Int
 }
 
 // NewMultiplier creates and returns a CRTMultiplier with the given parameters,
 // after proper sanitization.
 func NewMultiplier(n int, mod *big.Int) *Multiplier {
-	if !isPowerOfTwo(n) {
-		panic("multiplier expects `n` power of two")
-	}
-	if !mod.ProbablyPrime(32) {
-		panic("multiplier expects prime modulus")
-	}
-	one := new(big.Int)
-	if one.Mod(mod, big.NewInt(int64(2*n))).Cmp(big.NewInt(1)) != 0 {
-		panic("q != 1 mod 2n")
+	checkMultiplierParameters(n, mod)
+	return newMultiplier(n, mod, FindPrimitiveRootOfUnity(2*n, mod))
+}
+
+// NewMultiplierWithRoot creates and returns a Multiplier whose NTT uses the
+// given primitive 2n-th root of unity ψ. The NTT of a stores a(ψ^{2·rev(i)+1})
+// at index i, where rev reverses the log2(n) bits of i, so that fixing ψ fixes
+// the NTT representation, as standards such as FIPS 204 require.
+func NewMultiplierWithRoot(n int, mod, root *big.Int) *Multiplier {
+	checkMultiplierParameters(n, mod)
+	minusOne := new(big.Int).Sub(mod, big.NewInt(1))
+	if new(big.Int).Exp(root, big.NewInt(int64(n)), mod).Cmp(minusOne) != 0 {
+		panic("root is not a primitive 2n-th root of unity")
 	}
+	return newMultiplier(n, mod, root)
+}
+
+// newMultiplier builds the Multiplier for parameters and root that the caller
+// has already validated.
+func newMultiplier(n int, mod, root *big.Int) *Multiplier {
 	m := new(Multiplier)
 	m.N = n
 	m.Mod = mod
 
 	m.nInvQ = modularInverse(big.NewInt(int64(n)), mod)
 
-	g := FindPrimitiveRootOfUnity(2*n, mod)
+	g := new(big.Int).Mod(root, mod)
 	gInv := modularInverse(g, mod)
 	m.rootsBitReverse = rootsOfUnityBitReverse(n, g, mod)
 	m.invRootsBitReverse = rootsOfUnityBitReverse(n, gInv, mod)
+	m.root = g
 	return m
 }
 
@@ -59,3 +72,16 @@ func (mul *Multiplier) Mul(x, y *Polynomial) *Polynomial {
 	}
 	return c
 }
+
+func checkMultiplierParameters(n int, mod *big.Int) {
+	if !isPowerOfTwo(n) {
+		panic("multiplier expects `n` power of two")
+	}
+	if !mod.ProbablyPrime(32) {
+		panic("multiplier expects prime modulus")
+	}
+	one := new(big.Int)
+	if one.Mod(mod, big.NewInt(int64(2*n))).Cmp(big.NewInt(1)) != 0 {
+		panic("q != 1 mod 2n")
+	}
+}
@@ -0,0 +1,151 @@
+package negacyclic
+
+import (
+	"math"
+	"math/big"
+)
+
+// L1Norm returns the l-1 norm of the coefficient vector of p. If q is not
+// nil, the norm is computed on the representative of p modulo q with
+// coefficients in (-q/2, q/2].
+func (p *Polynomial) L1Norm(q *big.Int) *big.Int {
+	return L1Norm(p.centered(q).Coeffs)
+}
+
+// L2NormSquared returns the exact squared l-2 norm of the coefficient vector
+// of p. If q is not nil, the norm is computed on the representative of p
+// modulo q with coefficients in (-q/2, q/2].
+func (p *Polynomial) L2NormSquared(q *big.Int) *big.Int {
+	norm, aux := new(big.Int), new(big.Int)
+	for _, coeff := range p.centered(q).Coeffs {
+		norm.Add(norm, aux.Mul(coeff, coeff))
+	}
+	return norm
+}
+
+// L2Norm returns the l-2 norm of the coefficient vector of p, as a float. If q
+// is not nil, the norm is computed on the representative of p modulo q with
+// coefficients in (-q/2, q/2].
+func (p *Polynomial) L2Norm(q *big.Int) float64 {
+	return sqrtFloat(p.L2NormSquared(q))
+}
+
+// InfNorm returns the l-∞ norm of the coefficient vector of p. If q is not
+// nil, the norm is computed on the representative of p modulo q with
+// coefficients in (-q/2, q/2].
+func (p *Polynomial) InfNorm(q *big.Int) *big.Int {
+	norm := new(big.Int)
+	for _, coeff := range p.centered(q).Coeffs {
+		if norm.CmpAbs(coeff) < 0 {
+			norm.Abs(coeff)
+		}
+	}
+	return norm
+}
+
+// CanonicalInfNorm returns the l-∞ norm of the canonical embedding of p, that
+// is, the largest modulus of the evaluations of p at the primitive 2N-th
+// complex roots of unity. If q is not nil, the norm is computed on the
+// representative of p modulo q with coefficients in (-q/2, q/2].
+func (p *Polynomial) CanonicalInfNorm(q *big.Int) float64 {
+	var norm float64
+	for _, val := range CanonicalEmbedding(p.centered(q)) {
+		norm = math.Max(norm, math.Hypot(real(val), imag(val)))
+	}
+	return norm
+}
+
+// L1Norm returns the l-1 norm of v. If q is not zero, the norm is computed on
+// the representative of v modulo q with coordinates in (-q/2, q/2].
+func (v *Vector) L1Norm(q int) int {
+	norm := 0
+	for _, coeff := range v.Coeffs {
+		norm += abs(centeredMod(coeff, q))
+	}
+	return norm
+}
+
+// L2NormSquared returns the exact squared l-2 norm of v. If q is not zero,
+// the norm is computed on the representative of v modulo q with coordinates
+// in (-q/2, q/2].
+func (v *Vector) L2NormSquared(q int) *big.Int {
+	norm, aux := new(big.Int), new(big.Int)
+	for _, coeff := range v.Coeffs {
+		aux.SetInt64(int64(centeredMod(coeff, q)))
+		norm.Add(norm, aux.Mul(aux, aux))
+	}
+	return norm
+}
+
+// L2Norm returns the l-2 norm of v, as a float. If q is not zero, the norm is
+// computed on the representative of v modulo q with coordinates in
+// (-q/2, q/2].
+func (v *Vector) L2Norm(q int) float64 {
+	return sqrtFloat(v.L2NormSquared(q))
+}
+
+// InfNorm returns the l-∞ norm of v. If q is not zero, the norm is computed on
+// the representative of v modulo q with coordinates in (-q/2, q/2].
+func (v *Vector) InfNorm(q int) int {
+	norm := 0
+	for _, coeff := range v.Coeffs {
+		if val := abs(centeredMod(coeff, q)); val > norm {
+			norm = val
+		}
+	}
+	return norm
+}
+
+// CanonicalInfNorm returns the l-∞ norm of the canonical embedding of v,
+// interpreted as a polynomial. If q is not zero, the norm is computed on the
+// representative of v modulo q with coordinates in (-q/2, q/2].
+func (v *Vector) CanonicalInfNorm(q int) float64 {
+	var mod *big.Int
+	if q != 0 {
+		mod = big.NewInt(int64(q))
+	}
+	return v.Polynomial().CanonicalInfNorm(mod)
+}
+
+//
+// Internal
+//
+
+// centered returns p itself if q is nil, and otherwise a copy of p reduced
+// modulo q in the symmetric range.
+func (p *Polynomial) centered(q *big.Int) *Polynomial {
+	if q == nil {
+		return p
+	}
+	return p.Copy().Mod(q)
+}
+
+// centeredMod returns the representative of x modulo q in (-q/2, q/2], or x
+// itself if q is zero.
+func centeredMod(x, q int) int {
+	if q == 0 {
+		return x
+	}
+	x %= q
+	if x < 0 {
+		x += q
+	}
+	if x > q/2 {
+		x -= q
+	}
+	return x
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func sqrtFloat(x *big.Int) float64 {
+	sqrt := new(big.Float).SetInt(x)
+	sqrt.Sqrt(sqrt)
+	f, _ := sqrt.Float64()
+	return f
+}
@@ -0,0 +1,120 @@
+package negacyclic_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"negacyclic"
+)
+
+func TestNorms(t *testing.T) {
+	t.Run("polynomial", testPolynomialNorms)
+	t.Run("polynomial_centered", testPolynomialNormsCentered)
+	t.Run("vector", testVectorNorms)
+	t.Run("vector_centered", testVectorNormsCentered)
+	t.Run("canonical", testCanonicalNorm)
+	t.Run("zero", testZeroNorms)
+}
+
+func testPolynomialNorms(t *testing.T) {
+	p := polynomialFromInts(3, -4, 0, 1)
+	if norm := p.L1Norm(nil); norm.Int64() != 8 {
+		t.Errorf("l-1 norm: expected 8, got %d", norm)
+	}
+	if norm := p.L2NormSquared(nil); norm.Int64() != 26 {
+		t.Errorf("squared l-2 norm: expected 26, got %d", norm)
+	}
+	if norm := p.L2Norm(nil); math.Abs(norm-math.Sqrt(26)) > 1e-12 {
+		t.Errorf("l-2 norm: expected %f, got %f", math.Sqrt(26), norm)
+	}
+	if norm := p.InfNorm(nil); norm.Int64() != 4 {
+		t.Errorf("l-∞ norm: expected 4, got %d", norm)
+	}
+}
+
+func testPolynomialNormsCentered(t *testing.T) {
+	// Modulo 17, (16, 13, 0, 1) is represented by (-1, -4, 0, 1).
+	q := big.NewInt(17)
+	p := polynomialFromInts(16, 13, 0, 1)
+	if norm := p.L1Norm(q); norm.Int64() != 6 {
+		t.Errorf("l-1 norm: expected 6, got %d", norm)
+	}
+	if norm := p.L2NormSquared(q); norm.Int64() != 18 {
+		t.Errorf("squared l-2 norm: expected 18, got %d", norm)
+	}
+	if norm := p.InfNorm(q); norm.Int64() != 4 {
+		t.Errorf("l-∞ norm: expected 4, got %d", norm)
+	}
+	if p.Coeffs[0].Int64() != 16 {
+		t.Error("centered norm mutated the polynomial")
+	}
+}
+
+func testVectorNorms(t *testing.T) {
+	v := negacyclic.VectorFromSlice([]int{3, -4, 0, 1})
+	if norm := v.L1Norm(0); norm != 8 {
+		t.Errorf("l-1 norm: expected 8, got %d", norm)
+	}
+	if norm := v.L2NormSquared(0); norm.Int64() != 26 {
+		t.Errorf("squared l-2 norm: expected 26, got %d", norm)
+	}
+	if norm := v.L2Norm(0); math.Abs(norm-math.Sqrt(26)) > 1e-12 {
+		t.Errorf("l-2 norm: expected %f, got %f", math.Sqrt(26), norm)
+	}
+	if norm := v.InfNorm(0); norm != 4 {
+		t.Errorf("l-∞ norm: expected 4, got %d", norm)
+	}
+}
+
+func testVectorNormsCentered(t *testing.T) {
+	// Modulo 8, (4, -4, 5, 7) is represented by (4, 4, -3, -1).
+	v := negacyclic.VectorFromSlice([]int{4, -4, 5, 7})
+	if norm := v.L1Norm(8); norm != 12 {
+		t.Errorf("l-1 norm: expected 12, got %d", norm)
+	}
+	if norm := v.L2NormSquared(8); norm.Int64() != 42 {
+		t.Errorf("squared l-2 norm: expected 42, got %d", norm)
+	}
+	if norm := v.InfNorm(8); norm != 4 {
+		t.Errorf("l-∞ norm: expected 4, got %d", norm)
+	}
+}
+
+func testCanonicalNorm(t *testing.T) {
+	// The monomial X^k evaluates to roots of unity, and 1 + X evaluates to
+	// 2cos(π/2N) at ζ.
+	n := 16
+	p := negacyclic.NewPolynomial(n)
+	p.Coeffs[3].SetInt64(1)
+	if norm := p.CanonicalInfNorm(nil); math.Abs(norm-1) > 1e-12 {
+		t.Errorf("monomial: expected 1, got %f", norm)
+	}
+	p.Coeffs[0].SetInt64(1)
+	p.Coeffs[3].SetInt64(0)
+	p.Coeffs[1].SetInt64(1)
+	expected := 2 * math.Cos(math.Pi/float64(2*n))
+	if norm := p.CanonicalInfNorm(nil); math.Abs(norm-expected) > 1e-12 {
+		t.Errorf("1 + X: expected %f, got %f", expected, norm)
+	}
+	v := negacyclic.NewVector(n)
+	v.Coeffs[0], v.Coeffs[1] = 1, 97
+	if norm := v.CanonicalInfNorm(97); math.Abs(norm-1) > 1e-12 {
+		t.Errorf("1 + 97X mod 97: expected 1, got %f", norm)
+	}
+}
+
+func testZeroNorms(t *testing.T) {
+	p := negacyclic.NewPolynomial(8)
+	if p.L1Norm(nil).Sign() != 0 || p.L2Norm(nil) != 0 || p.InfNorm(nil).Sign() != 0 {
+		t.Error("non-zero norm of the zero polynomial")
+	}
+}
+
+func polynomialFromInts(coeffs ...int64) *negacyclic.Polynomial {
+	p := negacyclic.NewPolynomial(len(coeffs))
+	for i, coeff := range coeffs {
+		p.Coeffs[i].SetInt64(coeff)
+	}
+	return p
+}
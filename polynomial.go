@@ -54,6 +54,15 @@ func PolynomialFromSlice(slice []*big.Int) *Polynomial {
 	return &Polynomial{Coeffs: slice}
 }
 
+// Copy returns a deep copy of p.
+func (p *Polynomial) Copy() *Polynomial {
+	coeffs := make([]*big.Int, p.Deg())
+	for i, coeff := range p.Coeffs {
+		coeffs[i] = new(big.Int).Set(coeff)
+	}
+	return &Polynomial{Coeffs: coeffs}
+}
+
 func (p *Polynomial) symmetricModulus(q *big.Int) {
 	if q == nil {
 		return
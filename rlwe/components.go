@@ -0,0 +1,44 @@
+package rlwe
+
+import (
+	"math/big"
+
+	"negacyclic"
+)
+
+// AddComponents returns the component-wise sum modulo q of the components of
+// two ciphertexts of possibly different degrees, that is, the components of a
+// ciphertext whose phase is the sum of their phases.
+func AddComponents(q *big.Int, x, y []*negacyclic.Polynomial) []*negacyclic.Polynomial {
+	if len(x) < len(y) {
+		x, y = y, x
+	}
+	sum := make([]*negacyclic.Polynomial, len(x))
+	for i := range x {
+		if i < len(y) {
+			sum[i] = negacyclic.Add(x[i], y[i])
+		} else {
+			sum[i] = x[i].Copy()
+		}
+		reduce(sum[i], q)
+	}
+	return sum
+}
+
+// Tensor returns the components (c0·c0', c0·c1' + c1·c0', c1·c1') modulo q of
+// the product of two ciphertexts (c0, c1) and (c0', c1') of degree 1, with
+// products computed by mul. Its phase under s is the product of their phases.
+func Tensor(mul RingMultiplier, q *big.Int, x, y []*negacyclic.Polynomial) []*negacyclic.Polynomial {
+	if len(x) != 2 || len(y) != 2 {
+		panic("tensor of ciphertexts of degree 1 only")
+	}
+	prod := []*negacyclic.Polynomial{
+		mul.Mul(x[0], y[0]),
+		negacyclic.Add(mul.Mul(x[0], y[1]), mul.Mul(x[1], y[0])),
+		mul.Mul(x[1], y[1]),
+	}
+	for _, c := range prod {
+		reduce(c, q)
+	}
+	return prod
+}
@@ -0,0 +1,83 @@
+package rlwe
+
+import (
+	"math"
+	"sync"
+
+	"negacyclic"
+)
+
+// Distribution is a distribution over small ring elements, used to sample
+// secrets and errors. Coefficients are expected to be independent, of mean 0
+// and of the given variance.
+type Distribution interface {
+	// Sample returns a ring element of degree n, drawn from src.
+	Sample(n int, src negacyclic.Source) *negacyclic.Vector
+	// Variance returns the variance of each coefficient of a ring element
+	// of degree n.
+	Variance(n int) float64
+}
+
+// UniformTernary is the distribution over {0, ±1}^n where each coefficient is
+// +1, 0 or -1 with probability 1/4, 1/2 and 1/4 respectively.
+type UniformTernary struct{}
+
+// Sample returns a ring element of degree n, see negacyclic.ZO.
+func (UniformTernary) Sample(n int, src negacyclic.Source) *negacyclic.Vector {
+	return negacyclic.ZOFrom(n, .5, src)
+}
+
+// Variance returns 1/2.
+func (UniformTernary) Variance(n int) float64 {
+	return .5
+}
+
+// Gaussian is the discrete Gaussian distribution D_{Z,σ} of parameter
+// σ = Sigma, truncated to [-τσ, τσ] for the tail cut τ = TailCut, or 6 if
+// TailCut is 0.
+type Gaussian struct {
+	Sigma   float64
+	TailCut float64
+}
+
+// DefaultTailCut is the tail cut of a Gaussian whose TailCut is 0.
+const DefaultTailCut = 6
+
+// Sample returns a ring element of degree n, see negacyclic.GaussianCDT.
+func (g Gaussian) Sample(n int, src negacyclic.Source) *negacyclic.Vector {
+	if g.Sigma == 0 {
+		return negacyclic.NewVector(n)
+	}
+	return g.cdt().SampleVector(n, src)
+}
+
+// Variance returns the variance Σ x²·ρ(x) / Σ ρ(x) of the truncated
+// distribution, for ρ(x) = exp(-x²/(2σ²)) and |x| <= ⌈τσ⌉, which is close to
+// σ².
+func (g Gaussian) Variance(n int) float64 {
+	if g.Sigma == 0 {
+		return 0
+	}
+	bound := g.cdt().Bound()
+	num, den := 0., 1.
+	for x := 1; x <= bound; x++ {
+		rho := math.Exp(-float64(x*x) / (2 * g.Sigma * g.Sigma))
+		num += 2 * float64(x*x) * rho
+		den += 2 * rho
+	}
+	return num / den
+}
+
+// gaussianTables caches the CDT of each Gaussian, which is costly to compute.
+var gaussianTables sync.Map
+
+func (g Gaussian) cdt() *negacyclic.GaussianCDT {
+	if g.TailCut == 0 {
+		g.TailCut = DefaultTailCut
+	}
+	if cdt, ok := gaussianTables.Load(g); ok {
+		return cdt.(*negacyclic.GaussianCDT)
+	}
+	cdt, _ := gaussianTables.LoadOrStore(g, negacyclic.NewGaussianCDT(g.Sigma, g.TailCut))
+	return cdt.(*negacyclic.GaussianCDT)
+}
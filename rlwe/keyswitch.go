@@ -0,0 +1,133 @@
+package rlwe
+
+import (
+	"math/big"
+
+	"negacyclic"
+)
+
+// KeySwitchingKey allows to turn a ciphertext component that multiplies a
+// secret s' into a ciphertext under the secret key s. It consists of the
+// encryptions under s of B^i·s', for the gadget base B and i = 0, ..., l-1,
+// with B^l >= q.
+type KeySwitchingKey struct {
+	Base *big.Int
+	Keys []*Ciphertext
+}
+
+// RingMultiplier multiplies elements of a negacyclic ring R_q, such as
+// negacyclic.Multiplier or a level of negacyclic.RNSMultiplier.
+type RingMultiplier interface {
+	Mul(x, y *negacyclic.Polynomial) *negacyclic.Polynomial
+}
+
+// GenKeySwitchingKey returns a key switching key from the secret `from` to the
+// secret key sk, with gadget base `base`, drawing its randomness from src.
+func (params *Parameters) GenKeySwitchingKey(sk *SecretKey, from *negacyclic.Polynomial, base *big.Int, src negacyclic.Source) *KeySwitchingKey {
+	if from.Deg() != params.N {
+		panic("secret of unexpected degree")
+	}
+	return NewKeySwitchingKey(from, base, params.Q, func(pt *negacyclic.Polynomial) *Ciphertext {
+		return params.EncryptSymmetric(sk, pt, src)
+	})
+}
+
+// NewKeySwitchingKey returns the key switching key from the secret `from`
+// made of the encryptions by `encrypt` of B^i·s', for the gadget base B and
+// i = 0, ..., l-1, with B^l >= q. It lets schemes with their own encryption,
+// such as over a chain of moduli, share key switching.
+func NewKeySwitchingKey(from *negacyclic.Polynomial, base, q *big.Int, encrypt func(pt *negacyclic.Polynomial) *Ciphertext) *KeySwitchingKey {
+	levels := GadgetLevels(base, q)
+	ksk := &KeySwitchingKey{Base: new(big.Int).Set(base), Keys: make([]*Ciphertext, levels)}
+	pow := big.NewInt(1)
+	for i := range ksk.Keys {
+		pt := from.Copy()
+		pt.Scale(pow)
+		ksk.Keys[i] = encrypt(pt)
+		pow.Mul(pow, base)
+	}
+	return ksk
+}
+
+// KeySwitch returns a ciphertext under the secret key s whose phase is c·s',
+// up to the noise Σ d_i·e_i, where the d_i are the signed digits of c in base
+// B, and s' is the origin secret of ksk.
+func (params *Parameters) KeySwitch(c *negacyclic.Polynomial, ksk *KeySwitchingKey) *Ciphertext {
+	return ksk.Switch(params.multiplier, params.Q, c)
+}
+
+// Switch returns the ciphertext (Σ d_i·k_i0, Σ d_i·k_i1) mod q, where the
+// d_i are the signed digits of c mod q in base B and (k_i0, k_i1) are the keys
+// of ksk, with products computed by mul. A key generated modulo Q also
+// switches modulo any divisor q of Q, with fewer digits.
+func (ksk *KeySwitchingKey) Switch(mul RingMultiplier, q *big.Int, c *negacyclic.Polynomial) *Ciphertext {
+	levels := GadgetLevels(ksk.Base, q)
+	if levels > len(ksk.Keys) {
+		panic("key switching key too short for the modulus")
+	}
+	digits := negacyclic.Decompose(c.Copy().Mod(q), ksk.Base, levels)
+	n := c.Deg()
+	c0, c1 := negacyclic.NewPolynomial(n), negacyclic.NewPolynomial(n)
+	for i, digit := range digits {
+		c0 = negacyclic.Add(c0, mul.Mul(digit, ksk.Keys[i].C0))
+		c1 = negacyclic.Add(c1, mul.Mul(digit, ksk.Keys[i].C1))
+	}
+	reduce(c0, q)
+	reduce(c1, q)
+	return &Ciphertext{C0: c0, C1: c1}
+}
+
+// Relinearize returns the components (c0, c1) + Switch(c2) mod q of the
+// ciphertext of degree 1 with the same phase as the ciphertext (c0, c1, c2)
+// of degree 2, where ksk switches from s^2 to s.
+func (ksk *KeySwitchingKey) Relinearize(mul RingMultiplier, q *big.Int, value []*negacyclic.Polynomial) []*negacyclic.Polynomial {
+	if len(value) != 3 {
+		panic("relinearization expects a ciphertext of degree 2")
+	}
+	ks := ksk.Switch(mul, q, value[2])
+	c0 := negacyclic.Add(value[0], ks.C0)
+	c1 := negacyclic.Add(value[1], ks.C1)
+	reduce(c0, q)
+	reduce(c1, q)
+	return []*negacyclic.Polynomial{c0, c1}
+}
+
+// EncryptSymmetric returns an encryption (-a·s + e + pt, a) of the plaintext
+// polynomial pt under the secret key, without any encoding, where a and e are
+// drawn from src.
+func (params *Parameters) EncryptSymmetric(sk *SecretKey, pt *negacyclic.Polynomial, src negacyclic.Source) *Ciphertext {
+	e := params.Error.Sample(params.N, src).Polynomial()
+	return EncryptSymmetricMod(params.multiplier, params.Q, sk, pt, e, src)
+}
+
+// EncryptSymmetricMod returns the encryption (-a·s + e + pt, a) modulo q of
+// the plaintext polynomial pt under the secret key, for a uniform a drawn from
+// src and the error e, with products computed by mul.
+func EncryptSymmetricMod(mul RingMultiplier, q *big.Int, sk *SecretKey, pt, e *negacyclic.Polynomial, src negacyclic.Source) *Ciphertext {
+	a := negacyclic.PolynomialFromSlice(negacyclic.UniformModFrom(pt.Deg(), q, src))
+	b := mul.Mul(a, sk.S.Polynomial())
+	b.Negate()
+	b = negacyclic.Add(b, e)
+	b = negacyclic.Add(b, pt)
+	reduce(b, q)
+	return &Ciphertext{C0: b, C1: a}
+}
+
+// GadgetLevels returns the least l such that B^l >= q, for B = base.
+func (params *Parameters) GadgetLevels(base *big.Int) int {
+	return GadgetLevels(base, params.Q)
+}
+
+// GadgetLevels returns the least l such that B^l >= q, for B = base.
+func GadgetLevels(base, q *big.Int) int {
+	if base.Cmp(big.NewInt(2)) < 0 {
+		panic("gadget base must be at least 2")
+	}
+	levels := 1
+	pow := new(big.Int).Set(base)
+	for pow.Cmp(q) < 0 {
+		pow.Mul(pow, base)
+		levels++
+	}
+	return levels
+}
@@ -0,0 +1,84 @@
+package rlwe
+
+import (
+	"math/big"
+
+	"negacyclic"
+)
+
+// LWECiphertext is an LWE ciphertext (a, b) of dimension N modulo q, with
+// phase b + <a, s> for the coefficient vector s of the secret key.
+type LWECiphertext struct {
+	A []*big.Int
+	B *big.Int
+}
+
+// SampleExtract returns the LWE ciphertext, under the coefficients of the
+// secret key, whose phase is the coefficient i of the phase of ct. Since
+// (c1·s)_i = Σ_{j<=i} c1_{i-j}·s_j - Σ_{j>i} c1_{N+i-j}·s_j, the mask is c1
+// reversed around i, with the wrapped coefficients negated.
+func (params *Parameters) SampleExtract(ct *Ciphertext, i int) *LWECiphertext {
+	n := params.N
+	if i < 0 || i >= n {
+		panic("coefficient index out of range")
+	}
+	a := make([]*big.Int, n)
+	for j := range a {
+		if j <= i {
+			a[j] = new(big.Int).Set(ct.C1.Coeffs[i-j])
+		} else {
+			a[j] = new(big.Int).Neg(ct.C1.Coeffs[n+i-j])
+			a[j].Mod(a[j], params.Q)
+		}
+	}
+	return &LWECiphertext{A: a, B: new(big.Int).Set(ct.C0.Coeffs[i])}
+}
+
+// LWEPhase returns b + <a, s> mod q, in (-q/2, q/2].
+func (params *Parameters) LWEPhase(sk *SecretKey, ct *LWECiphertext) *big.Int {
+	if len(ct.A) != params.N {
+		panic("LWE ciphertext of unexpected dimension")
+	}
+	phase := new(big.Int).Set(ct.B)
+	aux := new(big.Int)
+	for j, a := range ct.A {
+		aux.Mul(a, big.NewInt(int64(sk.S.Coeffs[j])))
+		phase.Add(phase, aux)
+	}
+	return centerMod(phase, params.Q)
+}
+
+// DecryptLWE returns the bit encrypted by ct, see Decrypt.
+func (params *Parameters) DecryptLWE(sk *SecretKey, ct *LWECiphertext) int {
+	quarter := new(big.Int).Quo(params.Q, big.NewInt(4))
+	if params.LWEPhase(sk, ct).CmpAbs(quarter) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// LWEToRLWE returns an RLWE ciphertext whose phase has constant coefficient
+// equal to the phase of ct. The other coefficients of the phase are garbage.
+func (params *Parameters) LWEToRLWE(ct *LWECiphertext) *Ciphertext {
+	n := params.N
+	if len(ct.A) != n {
+		panic("LWE ciphertext of unexpected dimension")
+	}
+	c0, c1 := negacyclic.NewPolynomial(n), negacyclic.NewPolynomial(n)
+	c0.Coeffs[0].Mod(ct.B, params.Q)
+	c1.Coeffs[0].Mod(ct.A[0], params.Q)
+	for j := 1; j < n; j++ {
+		c1.Coeffs[n-j].Neg(ct.A[j]).Mod(c1.Coeffs[n-j], params.Q)
+	}
+	return &Ciphertext{C0: c0, C1: c1}
+}
+
+// centerMod returns x mod q in (-q/2, q/2].
+func centerMod(x, q *big.Int) *big.Int {
+	x.Mod(x, q)
+	half := new(big.Int).Rsh(q, 1)
+	if x.Cmp(half) > 0 {
+		x.Sub(x, q)
+	}
+	return x
+}
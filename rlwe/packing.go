@@ -0,0 +1,119 @@
+package rlwe
+
+import (
+	"math/big"
+
+	"negacyclic"
+)
+
+// GenAutomorphismKey returns the key switching key from τ_k(s) to s, where τ_k
+// is the automorphism X -> X^k, for an odd k, drawing its randomness from src.
+func (params *Parameters) GenAutomorphismKey(sk *SecretKey, k int, base *big.Int, src negacyclic.Source) *KeySwitchingKey {
+	return params.GenKeySwitchingKey(sk, sk.S.Automorphism(k).Polynomial(), base, src)
+}
+
+// Automorphism returns an encryption of τ_k(m) under the secret key, for a
+// ciphertext ct encrypting m and the automorphism key of k.
+func (params *Parameters) Automorphism(ct *Ciphertext, k int, key *KeySwitchingKey) *Ciphertext {
+	// (τ_k(c0), τ_k(c1)) has phase τ_k(m) under τ_k(s).
+	res := params.KeySwitch(ct.C1.Automorphism(k), key)
+	res.C0 = negacyclic.Add(res.C0, ct.C0.Automorphism(k))
+	params.reduce(res.C0)
+	return res
+}
+
+// GenPackingKeys returns the automorphism keys used by PackLWEs, indexed by
+// the exponents k = 2^j + 1, j = 1, ..., log2(N), drawing their randomness
+// from src.
+func (params *Parameters) GenPackingKeys(sk *SecretKey, base *big.Int, src negacyclic.Source) map[int]*KeySwitchingKey {
+	keys := make(map[int]*KeySwitchingKey)
+	for m := 2; m <= params.N; m *= 2 {
+		keys[m+1] = params.GenAutomorphismKey(sk, m+1, base, src)
+	}
+	return keys
+}
+
+// PackLWEs returns an RLWE ciphertext whose phase is Σ μ_i·X^{i·N/n}, where μ_i
+// is the phase of cts[i] and n = len(cts) is a power of two at most N. The
+// other coefficients of the phase are zero, up to the noise. It follows Chen,
+// Dai, Kim and Song, "Efficient Homomorphic Conversion Between (Ring) LWE
+// Ciphertexts", using the keys of GenPackingKeys. The key switching noise is
+// multiplied by up to N.
+func (params *Parameters) PackLWEs(cts []*LWECiphertext, keys map[int]*KeySwitchingKey) *Ciphertext {
+	n := len(cts)
+	if n == 0 || n > params.N || n&(n-1) != 0 {
+		panic("number of LWE ciphertexts must be a power of two at most N")
+	}
+	// Each step below doubles the phase, which the factor N^{-1} compensates.
+	nInv := new(big.Int).ModInverse(big.NewInt(int64(params.N)), params.Q)
+	if nInv == nil {
+		panic("N is not invertible modulo q")
+	}
+	rlwes := make([]*Ciphertext, n)
+	for i, ct := range cts {
+		rlwes[i] = params.LWEToRLWE(ct)
+		params.scale(rlwes[i], nInv)
+	}
+	res := params.pack(rlwes, keys)
+	// Zero the coefficients outside of multiples of N/n with the partial trace
+	// Σ τ_k over the remaining automorphisms.
+	for m := 2 * n; m <= params.N; m *= 2 {
+		rotated := params.Automorphism(res, m+1, packingKey(keys, m+1))
+		res = params.add(res, rotated)
+	}
+	return res
+}
+
+// pack recursively merges the ciphertexts, whose number is a power of two l,
+// into a ciphertext with phase l·Σ μ_i·X^{i·N/l} on the multiples of N/l.
+func (params *Parameters) pack(cts []*Ciphertext, keys map[int]*KeySwitchingKey) *Ciphertext {
+	l := len(cts)
+	if l == 1 {
+		return cts[0]
+	}
+	even := make([]*Ciphertext, l/2)
+	odd := make([]*Ciphertext, l/2)
+	for i := range even {
+		even[i], odd[i] = cts[2*i], cts[2*i+1]
+	}
+	ctEven, ctOdd := params.pack(even, keys), params.pack(odd, keys)
+	shift := params.N / l
+	shifted := &Ciphertext{
+		C0: negacyclic.MulMonomial(ctOdd.C0, shift),
+		C1: negacyclic.MulMonomial(ctOdd.C1, shift),
+	}
+	// τ_{l+1} fixes X^{i·N/l} for even i and negates it for odd i, so that
+	// (e + X^{N/l}·o) + τ_{l+1}(e - X^{N/l}·o) doubles both interleaved halves.
+	sum := params.add(ctEven, shifted)
+	diff := params.sub(ctEven, shifted)
+	return params.add(sum, params.Automorphism(diff, l+1, packingKey(keys, l+1)))
+}
+
+func (params *Parameters) add(x, y *Ciphertext) *Ciphertext {
+	res := &Ciphertext{C0: negacyclic.Add(x.C0, y.C0), C1: negacyclic.Add(x.C1, y.C1)}
+	params.reduce(res.C0)
+	params.reduce(res.C1)
+	return res
+}
+
+func (params *Parameters) sub(x, y *Ciphertext) *Ciphertext {
+	res := &Ciphertext{C0: negacyclic.Sub(x.C0, y.C0), C1: negacyclic.Sub(x.C1, y.C1)}
+	params.reduce(res.C0)
+	params.reduce(res.C1)
+	return res
+}
+
+func (params *Parameters) scale(ct *Ciphertext, c *big.Int) {
+	ct.C0.Scale(c)
+	ct.C1.Scale(c)
+	params.reduce(ct.C0)
+	params.reduce(ct.C1)
+}
+
+func packingKey(keys map[int]*KeySwitchingKey, k int) *KeySwitchingKey {
+	key, ok := keys[k]
+	if !ok {
+		panic("missing automorphism key")
+	}
+	return key
+}
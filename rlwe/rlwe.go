@@ -0,0 +1,192 @@
+// Package rlwe implements the public-key encryption scheme of Lyubashevsky,
+// Peikert and Regev (LPR) over the negacyclic ring R_q = Z_q[X]/(X^N+1), for
+// a prime q = 1 mod 2N.
+//
+// A secret key is a small element s of R. The public key is (b, a) with a
+// uniform in R_q and b = -a·s + e. A message m in {0, 1}^N is encrypted as
+// (c0, c1) = (b·u + e1 + ⌊q/2⌉·m, a·u + e2), where u is drawn from the secret
+// distribution and e, e1, e2 from the error distribution, so that the phase
+// c0 + c1·s = ⌊q/2⌉·m + e·u + e1 + e2·s decrypts to m as long as its noise is
+// smaller than q/4.
+package rlwe
+
+import (
+	"math"
+	"math/big"
+
+	"negacyclic"
+)
+
+// Parameters defines an instance of the scheme.
+type Parameters struct {
+	N          int
+	Q          *big.Int
+	Secret     Distribution
+	Error      Distribution
+	multiplier *negacyclic.Multiplier
+}
+
+// SecretKey is a small ring element s.
+type SecretKey struct {
+	S *negacyclic.Vector
+}
+
+// PublicKey is a pair (b, a) with b = -a·s + e mod q.
+type PublicKey struct {
+	B, A *negacyclic.Polynomial
+}
+
+// Ciphertext is a pair (c0, c1) of elements of R_q, with phase c0 + c1·s.
+type Ciphertext struct {
+	C0, C1 *negacyclic.Polynomial
+}
+
+// NewParameters creates and returns the parameters of the scheme for ring
+// degree n, prime modulus q = 1 mod 2n, and the given secret and error
+// distributions.
+func NewParameters(n int, q *big.Int, secret, err Distribution) *Parameters {
+	params := new(Parameters)
+	params.N = n
+	params.Q = q
+	params.Secret = secret
+	params.Error = err
+	params.multiplier = negacyclic.NewMultiplier(n, q)
+	return params
+}
+
+// Multiplier returns the multiplier in R_q used by the scheme.
+func (params *Parameters) Multiplier() *negacyclic.Multiplier {
+	return params.multiplier
+}
+
+// KeyGen samples and returns a key pair, drawing its randomness from src.
+func (params *Parameters) KeyGen(src negacyclic.Source) (*SecretKey, *PublicKey) {
+	sk := &SecretKey{S: params.Secret.Sample(params.N, src)}
+	return sk, params.PublicKey(sk, src)
+}
+
+// PublicKey samples and returns a public key for the given secret key,
+// drawing its randomness from src.
+func (params *Parameters) PublicKey(sk *SecretKey, src negacyclic.Source) *PublicKey {
+	ct := params.EncryptSymmetric(sk, negacyclic.NewPolynomial(params.N), src)
+	return &PublicKey{B: ct.C0, A: ct.C1}
+}
+
+// Encrypt returns an encryption of the binary message m in {0, 1}^N, drawing
+// its randomness from src.
+func (params *Parameters) Encrypt(pk *PublicKey, m *negacyclic.Vector, src negacyclic.Source) *Ciphertext {
+	if m.Len() != params.N {
+		panic("message of unexpected length")
+	}
+	for _, bit := range m.Coeffs {
+		if bit != 0 && bit != 1 {
+			panic("expects binary message")
+		}
+	}
+	delta := params.HalfQ()
+	pt := m.Polynomial()
+	pt.Scale(delta)
+	return params.EncryptPolynomial(pk, pt, src)
+}
+
+// EncryptPolynomial returns an encryption of the plaintext polynomial pt,
+// without any encoding, i.e. a ciphertext of phase pt + e·u + e1 + e2·s, where
+// u, e1 and e2 are drawn from src.
+func (params *Parameters) EncryptPolynomial(pk *PublicKey, pt *negacyclic.Polynomial, src negacyclic.Source) *Ciphertext {
+	if pt.Deg() != params.N {
+		panic("plaintext of unexpected degree")
+	}
+	u := params.Secret.Sample(params.N, src).Polynomial()
+	c0 := params.multiplier.Mul(pk.B, u)
+	c0 = negacyclic.Add(c0, params.Error.Sample(params.N, src))
+	c0 = negacyclic.Add(c0, pt)
+	c1 := params.multiplier.Mul(pk.A, u)
+	c1 = negacyclic.Add(c1, params.Error.Sample(params.N, src))
+	params.reduce(c0)
+	params.reduce(c1)
+	return &Ciphertext{C0: c0, C1: c1}
+}
+
+// Phase returns c0 + c1·s mod q, with coefficients in (-q/2, q/2].
+func (params *Parameters) Phase(sk *SecretKey, ct *Ciphertext) *negacyclic.Polynomial {
+	phase := params.multiplier.Mul(ct.C1, sk.S.Polynomial())
+	phase = negacyclic.Add(phase, ct.C0)
+	return phase.Mod(params.Q)
+}
+
+// Decrypt returns the binary message encrypted by ct.
+func (params *Parameters) Decrypt(sk *SecretKey, ct *Ciphertext) *negacyclic.Vector {
+	phase := params.Phase(sk, ct)
+	quarter := new(big.Int).Quo(params.Q, big.NewInt(4))
+	m := negacyclic.NewVector(params.N)
+	for i, coeff := range phase.Coeffs {
+		if coeff.CmpAbs(quarter) > 0 {
+			m.Coeffs[i] = 1
+		}
+	}
+	return m
+}
+
+// HalfQ returns ⌊q/2⌉, the scaling factor of the messages.
+func (params *Parameters) HalfQ() *big.Int {
+	delta := new(big.Int).Add(params.Q, big.NewInt(1))
+	return delta.Rsh(delta, 1)
+}
+
+// NoiseVariance returns the variance of each coefficient of the noise
+// e·u + e1 + e2·s of a fresh ciphertext, that is, V_e·(1 + 2N·V_s) for error
+// and secret variances V_e and V_s.
+func (params *Parameters) NoiseVariance() float64 {
+	n := params.N
+	ve := params.Error.Variance(n)
+	vs := params.Secret.Variance(n)
+	return ve * (1 + 2*float64(n)*vs)
+}
+
+// FailureProbability returns the probability that a given coefficient of a
+// fresh ciphertext decrypts incorrectly, that is, that its noise exceeds q/4
+// in absolute value. The noise is a sum of many independent products, and is
+// estimated by a normal distribution of variance NoiseVariance. The
+// probability that a whole message decrypts incorrectly is bounded by N times
+// this value.
+func (params *Parameters) FailureProbability() float64 {
+	quarter, _ := new(big.Float).Quo(new(big.Float).SetInt(params.Q), big.NewFloat(4)).Float64()
+	stdDev := math.Sqrt(params.NoiseVariance())
+	if stdDev == 0 {
+		return 0
+	}
+	return math.Erfc(quarter / (stdDev * math.Sqrt2))
+}
+
+// NoiseBudget returns the noise budget in bits of a ciphertext modulo q whose
+// noise has infinity norm `norm`, that is ⌊log2(q/2) - log2(norm)⌋, or 0 if
+// it is negative. It is the bit length of q when the noise is 0.
+func NoiseBudget(q, norm *big.Int) int {
+	if norm.Sign() == 0 {
+		return q.BitLen()
+	}
+	budget := math.Floor(log2(q) - 1 - log2(norm))
+	if budget < 0 {
+		return 0
+	}
+	return int(budget)
+}
+
+func (params *Parameters) reduce(p *negacyclic.Polynomial) {
+	reduce(p, params.Q)
+}
+
+// reduce maps the coefficients of p to [0, q).
+func reduce(p *negacyclic.Polynomial, q *big.Int) {
+	for _, coeff := range p.Coeffs {
+		coeff.Mod(coeff, q)
+	}
+}
+
+func log2(x *big.Int) float64 {
+	// x = mant·2^exp with mant in [0.5, 1).
+	mant := new(big.Float)
+	exp := new(big.Float).SetInt(x).MantExp(mant)
+	m, _ := mant.Float64()
+	return math.Log2(m) + float64(exp)
+}
@@ -0,0 +1,228 @@
+package rlwe_test
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"negacyclic"
+	"negacyclic/rlwe"
+)
+
+func TestRLWE(t *testing.T) {
+	t.Run("roundtrip", testRoundtrip)
+	t.Run("failure_rate", testFailureRate)
+	t.Run("key_switch", testKeySwitch)
+	t.Run("sample_extract", testSampleExtract)
+	t.Run("pack_lwes", testPackLWEs)
+	t.Run("secret_key_json", testSecretKeyJSON)
+	t.Run("seeded_source", testSeededSource)
+	t.Run("gaussian", testGaussian)
+}
+
+func testRoundtrip(t *testing.T) {
+	n := 1 << 10
+	q := negacyclic.RLWEPrime(30, 2*n)
+	params := rlwe.NewParameters(n, q, rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 3.2})
+	if p := params.FailureProbability(); p > 1e-30 {
+		t.Fatalf("unexpected failure probability %g", p)
+	}
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	for i := 0; i < 5; i++ {
+		m := randomBits(n)
+		got := params.Decrypt(sk, params.Encrypt(pk, m, negacyclic.DefaultSource))
+		for j := range m.Coeffs {
+			if got.Coeffs[j] != m.Coeffs[j] {
+				t.Fatalf("bit %d: expected %d, got %d", j, m.Coeffs[j], got.Coeffs[j])
+			}
+		}
+	}
+}
+
+// testFailureRate uses a modulus small enough for decryption failures to be
+// frequent, and compares their empirical rate with the analytic estimate.
+func testFailureRate(t *testing.T) {
+	n := 1 << 8
+	q := negacyclic.RLWEPrime(13, 2*n)
+	params := rlwe.NewParameters(n, q, rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 40})
+	expected := params.FailureProbability()
+	if expected < 1e-3 || expected > 1e-1 {
+		t.Fatalf("parameters lead to failure probability %g", expected)
+	}
+	trials := 100
+	failures := 0
+	for i := 0; i < trials; i++ {
+		sk, pk := params.KeyGen(negacyclic.DefaultSource)
+		m := randomBits(n)
+		got := params.Decrypt(sk, params.Encrypt(pk, m, negacyclic.DefaultSource))
+		for j := range m.Coeffs {
+			if got.Coeffs[j] != m.Coeffs[j] {
+				failures++
+			}
+		}
+	}
+	samples := float64(trials * n)
+	rate := float64(failures) / samples
+	// Coefficients of the same ciphertext are not independent, hence the
+	// generous tolerance.
+	tolerance := 5 * math.Sqrt(expected/samples)
+	t.Logf("failure rate %g, expected %g", rate, expected)
+	if rate > 1.5*expected+tolerance {
+		t.Errorf("failure rate %g exceeds the bound %g", rate, expected)
+	}
+	if rate < expected/1.5-tolerance {
+		t.Errorf("failure rate %g much lower than the estimate %g", rate, expected)
+	}
+}
+
+func testKeySwitch(t *testing.T) {
+	n := 1 << 8
+	q := negacyclic.RLWEPrime(60, 2*n)
+	params := rlwe.NewParameters(n, q, rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 3.2})
+	sk, _ := params.KeyGen(negacyclic.DefaultSource)
+	from := rlwe.UniformTernary{}.Sample(n, negacyclic.DefaultSource).Polynomial()
+	base := big.NewInt(1 << 12)
+	ksk := params.GenKeySwitchingKey(sk, from, base, negacyclic.DefaultSource)
+	if len(ksk.Keys) != 5 {
+		t.Fatalf("expected 5 levels, got %d", len(ksk.Keys))
+	}
+	c := negacyclic.PolynomialFromSlice(negacyclic.UniformMod(n, q))
+	expected := params.Multiplier().Mul(c, from)
+	noise := negacyclic.Sub(params.Phase(sk, params.KeySwitch(c, ksk)), expected)
+	// Σ d_i·e_i has variance about l·N·B^2/12·σ^2, that is, a deviation
+	// below 2^18.
+	bound := big.NewInt(1 << 25)
+	if norm := noise.InfNorm(q); norm.Cmp(bound) > 0 {
+		t.Fatalf("key switching noise %d exceeds %d", norm, bound)
+	}
+}
+
+func testSampleExtract(t *testing.T) {
+	n := 1 << 8
+	q := negacyclic.RLWEPrime(30, 2*n)
+	params := rlwe.NewParameters(n, q, rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 3.2})
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	ct := params.Encrypt(pk, randomBits(n), negacyclic.DefaultSource)
+	phase := params.Phase(sk, ct)
+	for i := 0; i < n; i++ {
+		lwe := params.SampleExtract(ct, i)
+		if got := params.LWEPhase(sk, lwe); got.Cmp(phase.Coeffs[i]) != 0 {
+			t.Fatalf("coefficient %d: expected phase %d, got %d", i, phase.Coeffs[i], got)
+		}
+		back := params.Phase(sk, params.LWEToRLWE(lwe))
+		if back.Coeffs[0].Cmp(phase.Coeffs[i]) != 0 {
+			t.Fatalf("coefficient %d: expected phase %d after conversion, got %d",
+				i, phase.Coeffs[i], back.Coeffs[0])
+		}
+	}
+}
+
+func testPackLWEs(t *testing.T) {
+	n := 1 << 6
+	q := negacyclic.RLWEPrime(60, 2*n)
+	params := rlwe.NewParameters(n, q, rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 3.2})
+	sk, pk := params.KeyGen(negacyclic.DefaultSource)
+	keys := params.GenPackingKeys(sk, big.NewInt(1<<12), negacyclic.DefaultSource)
+	for _, count := range []int{1, 4, n} {
+		bits := randomBits(count)
+		lwes := make([]*rlwe.LWECiphertext, count)
+		for i := range lwes {
+			// Extract the bit from a different coefficient of its own
+			// ciphertext.
+			m := negacyclic.NewVector(n)
+			m.Coeffs[i] = bits.Coeffs[i]
+			lwes[i] = params.SampleExtract(params.Encrypt(pk, m, negacyclic.DefaultSource), i)
+			if got := params.DecryptLWE(sk, lwes[i]); got != bits.Coeffs[i] {
+				t.Fatalf("LWE %d: expected %d, got %d", i, bits.Coeffs[i], got)
+			}
+		}
+		got := params.Decrypt(sk, params.PackLWEs(lwes, keys))
+		step := n / count
+		for j, bit := range got.Coeffs {
+			expected := 0
+			if j%step == 0 {
+				expected = bits.Coeffs[j/step]
+			}
+			if bit != expected {
+				t.Fatalf("%d ciphertexts, coefficient %d: expected %d, got %d", count, j, expected, bit)
+			}
+		}
+	}
+}
+
+func testSecretKeyJSON(t *testing.T) {
+	n := 1 << 6
+	params := rlwe.NewParameters(n, negacyclic.RLWEPrime(30, 2*n), rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 3.2})
+	sk, _ := params.KeyGen(negacyclic.DefaultSource)
+	data, err := json.Marshal(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := new(rlwe.SecretKey)
+	if err := json.Unmarshal(data, got); err != nil {
+		t.Fatal(err)
+	}
+	for i := range sk.S.Coeffs {
+		if got.S.Coeffs[i] != sk.S.Coeffs[i] {
+			t.Fatalf("coefficient %d: expected %d, got %d", i, sk.S.Coeffs[i], got.S.Coeffs[i])
+		}
+	}
+}
+
+// testSeededSource checks that key generation and encryption are deterministic
+// functions of their source.
+func testSeededSource(t *testing.T) {
+	n := 1 << 6
+	params := rlwe.NewParameters(n, negacyclic.RLWEPrime(30, 2*n), rlwe.UniformTernary{}, rlwe.Gaussian{Sigma: 3.2})
+	seed := make([]byte, negacyclic.SeedSize)
+	m := randomBits(n)
+	encrypt := func(nonce byte) *rlwe.Ciphertext {
+		src := negacyclic.NewSeededSource(seed, []byte{nonce})
+		_, pk := params.KeyGen(src)
+		return params.Encrypt(pk, m, src)
+	}
+	a, b, c := encrypt(1), encrypt(1), encrypt(2)
+	differ := false
+	for i := 0; i < n; i++ {
+		if a.C0.Coeffs[i].Cmp(b.C0.Coeffs[i]) != 0 || a.C1.Coeffs[i].Cmp(b.C1.Coeffs[i]) != 0 {
+			t.Fatalf("same source, different ciphertexts at %d", i)
+		}
+		differ = differ || a.C0.Coeffs[i].Cmp(c.C0.Coeffs[i]) != 0
+	}
+	if !differ {
+		t.Fatal("different sources, same ciphertexts")
+	}
+}
+
+// testGaussian checks the tail cut and the variance of the samples of the
+// Gaussian distribution.
+func testGaussian(t *testing.T) {
+	g := rlwe.Gaussian{Sigma: 3.2, TailCut: 4}
+	n := 1 << 16
+	v := g.Sample(n, negacyclic.DefaultSource)
+	sum := 0.
+	for _, x := range v.Coeffs {
+		if x < -13 || x > 13 {
+			t.Fatalf("sample %d beyond the tail cut", x)
+		}
+		sum += float64(x * x)
+	}
+	// The sample variance has standard deviation about sqrt(2/n)·σ².
+	variance := g.Variance(n)
+	if math.Abs(variance-3.2*3.2) > .1 {
+		t.Fatalf("unexpected variance %f", variance)
+	}
+	if got := sum / float64(n); math.Abs(got-variance) > 5*math.Sqrt(2/float64(n))*variance {
+		t.Fatalf("sample variance %f, expected %f", got, variance)
+	}
+}
+
+func randomBits(n int) *negacyclic.Vector {
+	m := negacyclic.NewVector(n)
+	for i := range m.Coeffs {
+		m.Coeffs[i] = rand.Intn(2)
+	}
+	return m
+}
@@ -0,0 +1,137 @@
+package negacyclic
+
+import "math/big"
+
+// RNSMultiplier handles the multiplication in a negacyclic ring of the form
+// Z_Q[X]/(X^n+1), where Q = q_0 q_1 ... q_L is a product of distinct primes
+// q_i = 1 mod 2n. Internally, it operates modulo each prime with NTT, and uses
+// the CRT. The primes are ordered as a chain: the ring at level l is the ring
+// modulo Q_l = q_0 ... q_l.
+type RNSMultiplier struct {
+	N      int
+	Mod    *big.Int
+	Moduli []*big.Int
+	// crtFactors[i] = (Q/q_i)·[(Q/q_i)^{-1}]_{q_i}.
+	crtFactors  []*big.Int
+	multipliers []*Multiplier
+	levels      []*RNSMultiplier
+}
+
+// NewRNSMultiplier creates and returns an RNSMultiplier with the given
+// parameters, after proper sanitization.
+func NewRNSMultiplier(n int, moduli []*big.Int) *RNSMultiplier {
+	if len(moduli) == 0 {
+		panic("multiplier expects at least one modulus")
+	}
+	for i := range moduli {
+		for j := 0; j < i; j++ {
+			if moduli[i].Cmp(moduli[j]) == 0 {
+				panic("multiplier expects distinct moduli")
+			}
+		}
+	}
+	multipliers := make([]*Multiplier, len(moduli))
+	for i, mod := range moduli {
+		multipliers[i] = NewMultiplier(n, mod)
+	}
+	levels := make([]*RNSMultiplier, len(moduli))
+	for l := range levels {
+		levels[l] = newRNSMultiplierLevel(n, multipliers[:l+1], levels)
+	}
+	return levels[len(levels)-1]
+}
+
+func newRNSMultiplierLevel(n int, multipliers []*Multiplier, levels []*RNSMultiplier) *RNSMultiplier {
+	m := new(RNSMultiplier)
+	m.N = n
+	m.multipliers = multipliers
+	m.levels = levels
+	m.Moduli = make([]*big.Int, len(multipliers))
+	m.Mod = big.NewInt(1)
+	for i, mul := range multipliers {
+		m.Moduli[i] = mul.Mod
+		m.Mod.Mul(m.Mod, mul.Mod)
+	}
+	m.crtFactors = make([]*big.Int, len(multipliers))
+	for i, qi := range m.Moduli {
+		quo := new(big.Int).Quo(m.Mod, qi)
+		inv := new(big.Int).Mod(quo, qi)
+		inv = modularInverse(inv, qi)
+		m.crtFactors[i] = inv.Mul(inv, quo)
+	}
+	return m
+}
+
+// Level returns the index l of the last prime of the chain, so that Mod is
+// Q_l = q_0 ... q_l.
+func (m *RNSMultiplier) Level() int {
+	return len(m.Moduli) - 1
+}
+
+// AtLevel returns the multiplier modulo Q_l = q_0 ... q_l, for l lower than or
+// equal to the level of m.
+func (m *RNSMultiplier) AtLevel(l int) *RNSMultiplier {
+	if l < 0 || l > m.Level() {
+		panic("level out of range")
+	}
+	return m.levels[l]
+}
+
+// Mul computes the product of x and y in the corresponding negacyclic ring.
+// The coefficients of the result lie in [0, Q).
+func (m *RNSMultiplier) Mul(x, y *Polynomial) *Polynomial {
+	if x.Deg() != y.Deg() {
+		panic("asymmetric multiplication call")
+	}
+	limbs := make([]*Polynomial, len(m.multipliers))
+	for i, mul := range m.multipliers {
+		limbs[i] = mul.Mul(reduce(x, mul.Mod), reduce(y, mul.Mod))
+	}
+	return m.reconstruct(limbs)
+}
+
+// reconstruct returns the polynomial modulo Q whose residue modulo q_i is
+// limbs[i], with coefficients in [0, Q).
+func (m *RNSMultiplier) reconstruct(limbs []*Polynomial) *Polynomial {
+	z := NewPolynomial(limbs[0].Deg())
+	aux := new(big.Int)
+	for j, coeff := range z.Coeffs {
+		for i, limb := range limbs {
+			coeff.Add(coeff, aux.Mul(limb.Coeffs[j], m.crtFactors[i]))
+		}
+		coeff.Mod(coeff, m.Mod)
+	}
+	return z
+}
+
+// reduce returns a copy of p with coefficients reduced in [0, q).
+func reduce(p *Polynomial, q *big.Int) *Polynomial {
+	r := NewPolynomial(p.Deg())
+	for i, coeff := range p.Coeffs {
+		r.Coeffs[i].Mod(coeff, q)
+	}
+	return r
+}
+
+// Rescale returns ⌊p/q_l⌉ mod Q_{l-1}, where q_l is the last prime of the
+// chain, with coefficients in [0, Q_{l-1}). Like ScaleNearest, but computed
+// independently modulo each prime q_i of Q_{l-1}, as
+// ([p]_{q_i} - r)·q_l^{-1} mod q_i, where r is the representative of p
+// modulo q_l in (-q_l/2, q_l/2].
+func (m *RNSMultiplier) Rescale(p *Polynomial) *Polynomial {
+	l := m.Level()
+	if l == 0 {
+		panic("cannot rescale below level 0")
+	}
+	ql := m.Moduli[l]
+	last := reduce(p, ql).Mod(ql)
+	limbs := make([]*Polynomial, l)
+	for i, qi := range m.Moduli[:l] {
+		qlInv := modularInverse(new(big.Int).Mod(ql, qi), qi)
+		limbs[i] = reduce(p, qi)
+		for j, coeff := range limbs[i].Coeffs {
+			coeff.Sub(coeff, last.Coeffs[j]).Mul(coeff, qlInv).Mod(coeff, qi)
+		}
+	}
+	return m.AtLevel(l - 1).reconstruct(limbs)
+}
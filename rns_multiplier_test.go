@@ -0,0 +1,78 @@
+package negacyclic_test
+
+import (
+	"math/big"
+	"testing"
+
+	"negacyclic"
+)
+
+func TestPolynomialRNSMultiplication(t *testing.T) {
+	t.Run("nttRNS", testNTTRNS)
+	t.Run("levels", testRNSLevels)
+	t.Run("rescale", testRNSRescale)
+}
+
+func testNTTRNS(t *testing.T) {
+	n := 1 << 8
+	moduli := negacyclic.RLWEPrimeChain(60, 2*n, 4)
+	m := negacyclic.NewRNSMultiplier(n, moduli)
+	x := randomElement(n, m.Mod)
+	y := randomElement(n, m.Mod)
+	naiveQ := negacyclic.Karatsuba(x, y)
+	naiveQ.Mod(m.Mod)
+	nttQ := m.Mul(x, y)
+	nttQ.Mod(m.Mod)
+	for i := range nttQ.Coeffs {
+		if nttQ.Coeffs[i].Cmp(naiveQ.Coeffs[i]) != 0 {
+			t.Fatal("incorrect result modulo Q")
+		}
+	}
+}
+
+func testRNSLevels(t *testing.T) {
+	n := 1 << 6
+	moduli := negacyclic.RLWEPrimeChain(40, 2*n, 3)
+	m := negacyclic.NewRNSMultiplier(n, moduli)
+	if m.Level() != 2 {
+		t.Fatalf("expected level 2, got %d", m.Level())
+	}
+	x := randomElement(n, m.Mod)
+	y := randomElement(n, m.Mod)
+	for l := 0; l <= m.Level(); l++ {
+		ml := m.AtLevel(l)
+		q := big.NewInt(1)
+		for _, qi := range moduli[:l+1] {
+			q.Mul(q, qi)
+		}
+		if ml.Mod.Cmp(q) != 0 {
+			t.Fatalf("level %d: unexpected modulus", l)
+		}
+		naiveQ := negacyclic.Karatsuba(x, y)
+		naiveQ.Mod(q)
+		nttQ := ml.Mul(x, y)
+		nttQ.Mod(q)
+		for i := range nttQ.Coeffs {
+			if nttQ.Coeffs[i].Cmp(naiveQ.Coeffs[i]) != 0 {
+				t.Fatalf("level %d: incorrect result", l)
+			}
+		}
+	}
+}
+
+func testRNSRescale(t *testing.T) {
+	n := 1 << 6
+	moduli := negacyclic.RLWEPrimeChain(50, 2*n, 3)
+	m := negacyclic.NewRNSMultiplier(n, moduli)
+	x := randomElement(n, m.Mod)
+	x.Mod(m.Mod)
+	expected := x.ScaleNearest(moduli[2])
+	expected.Mod(m.AtLevel(1).Mod)
+	got := m.Rescale(x)
+	got.Mod(m.AtLevel(1).Mod)
+	for i := range got.Coeffs {
+		if got.Coeffs[i].Cmp(expected.Coeffs[i]) != 0 {
+			t.Fatalf("coefficient %d: expected %d, got %d", i, expected.Coeffs[i], got.Coeffs[i])
+		}
+	}
+}
@@ -1,12 +1,10 @@
 package negacyclic
 
 import (
-	"crypto/rand"
 	"errors"
 	"math"
 	"math/big"
 	"math/bits"
-	mrand "math/rand"
 )
 
 // RLWEPrime samples a prime `q` of given bit length, satisfying the condition q
@@ -27,48 +25,60 @@ func RLWEPrime(bitLen, n int) *big.Int {
 	return prime
 }
 
-// HWT returns a uniformly sampled vector of {0, ±1}^dim and given hamming weight.
-func HWT(dim, hamming int) ([]int, error) {
-	if hamming > dim {
-		return nil, errors.New("impossible hamming weight")
-	}
-	vec := make([]int, dim)
-	var err error
-	for i := 0; i < hamming; i++ {
-		randIndex, err := rand.Int(rand.Reader, big.NewInt(int64(dim)))
-		if err != nil {
-			panic("fatal entropy error:" + err.Error())
-		}
-		index := randIndex.Int64()
-		if vec[index] != 0 {
-			i--
-			continue
-		}
-		coin, err := rand.Int(rand.Reader, big.NewInt(2))
-		if err != nil {
-			panic("fatal entropy error:" + err.Error())
+// RLWEPrimeChain returns `count` distinct primes of given bit length,
+// satisfying q = 1 mod n, in increasing order. As for RLWEPrime, these primes
+// are not sampled with a cryptographic random generator and MUST NOT be used
+// as secret values.
+func RLWEPrimeChain(bitLen, n, count int) []*big.Int {
+	chain := make([]*big.Int, count)
+	dim := big.NewInt(int64(n))
+	prime := RLWEPrime(bitLen, n)
+	for i := range chain {
+		if prime.BitLen() != bitLen {
+			panic("not enough primes of the given bit length")
 		}
-		if coin.Int64() == 0 {
-			vec[index] = 1
+		chain[i] = prime
+		prime = new(big.Int).Add(prime, dim)
+		for !prime.ProbablyPrime(32) {
+			prime.Add(prime, dim)
 		}
-		vec[index] = -1
 	}
-	return vec, err
+	return chain
+}
+
+// HWT returns a uniformly sampled vector of {0, ±1}^dim and given hamming
+// weight, drawn from DefaultSource. See TernaryHWT.
+func HWT(dim, hamming int) ([]int, error) {
+	return HWTFrom(dim, hamming, DefaultSource)
+}
+
+// HWTFrom is HWT with randomness drawn from src.
+func HWTFrom(dim, hamming int, src Source) ([]int, error) {
+	if hamming < 0 || hamming > dim {
+		return nil, errors.New("impossible hamming weight")
+	}
+	return TernaryHWT(dim, hamming, src).Coeffs, nil
 }
 
 // ZO draws a vector from {0, ±1}^dim where each entry is +1, 0 or -1 with
-// probability rho/2, 1-rho, and rho/2 respectively.
+// probability rho/2, 1-rho, and rho/2 respectively, from DefaultSource.
 func ZO(dim int, rho float64) *Vector {
+	return ZOFrom(dim, rho, DefaultSource)
+}
+
+// ZOFrom is ZO with randomness drawn from src. It is optimized for rho = .5,
+// and uses TernaryIID otherwise.
+func ZOFrom(dim int, rho float64, src Source) *Vector {
 	if rho != .5 {
-		panic("optimized for rho = .5. Use ZONaive")
+		return TernaryIID(dim, rho, src)
 	}
 	vec := make([]int, dim)
 	// Sample 2*dim bits
-	bytes := make([]byte, dim/4)
-	mrand.Read(bytes)
+	bytes := make([]byte, (dim+3)/4)
+	readFull(src, bytes)
 	index := 0
 	for _, b := range bytes {
-		for i := 0; i < 4; i++ {
+		for i := 0; i < 4 && index < dim; i++ {
 			if b&0x03 == 0x01 {
 				vec[index] = 1
 			}
@@ -82,47 +92,41 @@ func ZO(dim int, rho float64) *Vector {
 	return &Vector{Coeffs: vec}
 }
 
-// ZONaive draws a vector from {0, ±1}^dim where each entry is +1, 0 or -1 with
-// probability rho/2, 1-rho, and rho/2 respectively.
+// ZONaive draws a vector from {0, ±1}^dim with exactly ⌊rho·dim⌋ entries +1
+// and as many entries -1, from DefaultSource.
 func ZONaive(dim int, rho float64) *Vector {
-	vec := make([]int, dim)
-	for i := 0; i < int(rho*float64(dim)); i++ {
-		index := mrand.Intn(dim)
-		for vec[index] != 0 {
-			index = mrand.Intn(dim)
-		}
-		vec[index] = 1
-	}
-	for i := 0; i < int(rho*float64(dim)); i++ {
-		index := mrand.Intn(dim)
-		for vec[index] != 0 {
-			index = mrand.Intn(dim)
-		}
-		vec[index] = -1
-	}
-	return &Vector{Coeffs: vec}
+	return ZONaiveFrom(dim, rho, DefaultSource)
+}
+
+// ZONaiveFrom is ZONaive with randomness drawn from src. See TernaryCounts.
+func ZONaiveFrom(dim int, rho float64, src Source) *Vector {
+	count := int(rho * float64(dim))
+	return TernaryCounts(dim, count, count, src)
 }
 
 // UniformMod samples a polynomial of given degree with uniform coefficients in
-// Z/qZ.
+// Z/qZ, from DefaultSource.
 func UniformMod(deg int, q *big.Int) []*big.Int {
-	pol := make([]*big.Int, deg)
-	var err error
-	for i := 0; i < deg; i++ {
-		pol[i], err = rand.Int(rand.Reader, q)
-		if err != nil {
-			panic(err)
-		}
-	}
-	return pol
+	return UniformModFrom(deg, q, DefaultSource)
+}
+
+// UniformModFrom is UniformMod with randomness drawn from src.
+func UniformModFrom(deg int, q *big.Int, src Source) []*big.Int {
+	return uniformSlice(src, deg, q)
 }
 
 // DG samples a vector in Z^n by drawing each coefficient from
-// the discrete Gaussian distribution of mean 0 and the given std. deviation.
+// the discrete Gaussian distribution of mean 0 and the given std. deviation,
+// from DefaultSource.
 func DG(dim int, stdDev float64) []int {
+	return DGFrom(dim, stdDev, DefaultSource)
+}
+
+// DGFrom is DG with randomness drawn from src.
+func DGFrom(dim int, stdDev float64, src Source) []int {
 	vec := make([]int, dim)
 	for i := 0; i < dim; i++ {
-		vec[i] = int(math.Round(mrand.NormFloat64() * math.Sqrt(stdDev)))
+		vec[i] = int(math.Round(normFloat64(src) * math.Sqrt(stdDev)))
 	}
 	return vec
 }
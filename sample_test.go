@@ -1,6 +1,12 @@
 package negacyclic_test
 
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
 	"math/rand"
 	"testing"
 
@@ -9,9 +15,27 @@ import (
 
 func TestDistributions(t *testing.T) {
 	t.Run("RLWEprime", testRLWE)
+	t.Run("RLWEprimeChain", testRLWEChain)
 	t.Run("HWT", testHWT)
 	t.Run("DG", testDG)
 	t.Run("zeroDG", testZeroDG)
+	t.Run("seeded_source", testSeededSource)
+	t.Run("discrete_gaussian", testDiscreteGaussian)
+	t.Run("CDT_tail", testCDTTail)
+	t.Run("CBD", testCBD)
+	t.Run("CBD_bits", testCBDBits)
+	t.Run("CBD_seed", testCBDSeed)
+	t.Run("ternary_HWT", testTernaryHWT)
+	t.Run("ternary_counts", testTernaryCounts)
+	t.Run("ternary_IID", testTernaryIID)
+	t.Run("big_gaussian", testBigGaussian)
+	t.Run("big_gaussian_residues", testBigGaussianResidues)
+	t.Run("centered_gaussian", testCenteredGaussian)
+	t.Run("centered_gaussian_big", testCenteredGaussianBig)
+	t.Run("centered_gaussian_big_sigma", testCenteredGaussianBigSigma)
+	t.Run("coset_gaussian", testCosetGaussian)
+	t.Run("uniform_bounded", testUniformBounded)
+	t.Run("uniform_bounded_big", testUniformBoundedBig)
 }
 
 func testRLWE(t *testing.T) {
@@ -28,6 +52,26 @@ func testRLWE(t *testing.T) {
 	}
 }
 
+func testRLWEChain(t *testing.T) {
+	n := 1024
+	bitLen := 50
+	chain := negacyclic.RLWEPrimeChain(bitLen, n, 5)
+	for i, q := range chain {
+		if !q.ProbablyPrime(32) {
+			t.Fatal("not prime")
+		}
+		if q.BitLen() != bitLen {
+			t.Fatalf("prime of %d bits", q.BitLen())
+		}
+		if new(big.Int).Mod(q, big.NewInt(int64(n))).Int64() != 1 {
+			t.Fatal("q != 1 mod n")
+		}
+		if i > 0 && q.Cmp(chain[i-1]) <= 0 {
+			t.Fatal("chain not increasing")
+		}
+	}
+}
+
 func testHWT(t *testing.T) {
 	n := 1 + rand.Intn(512)
 	h := rand.Intn(n)
@@ -63,3 +107,550 @@ func testZeroDG(t *testing.T) {
 		}
 	}
 }
+
+// testSeededSource checks that every sampler is a deterministic function of
+// its source.
+func testSeededSource(t *testing.T) {
+	n := 256
+	q := negacyclic.RLWEPrime(40, 2*n)
+	seed := make([]byte, negacyclic.SeedSize)
+	source := func(nonce byte) negacyclic.Source {
+		return negacyclic.NewSeededSource(seed, []byte{nonce})
+	}
+	samplers := map[string]func(src negacyclic.Source) []int{
+		"HWT": func(src negacyclic.Source) []int {
+			v, err := negacyclic.HWTFrom(n, 64, src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return v
+		},
+		"ZO":      func(src negacyclic.Source) []int { return negacyclic.ZOFrom(n, .5, src).Coeffs },
+		"ZONaive": func(src negacyclic.Source) []int { return negacyclic.ZONaiveFrom(n, .25, src).Coeffs },
+		"DG":      func(src negacyclic.Source) []int { return negacyclic.DGFrom(n, 10, src) },
+		"UniformMod": func(src negacyclic.Source) []int {
+			v := make([]int, n)
+			for i, coeff := range negacyclic.UniformModFrom(n, q, src) {
+				v[i] = int(coeff.Int64())
+			}
+			return v
+		},
+	}
+	for name, sample := range samplers {
+		a, b, c := sample(source(1)), sample(source(1)), sample(source(2))
+		differ := false
+		for i := range a {
+			if a[i] != b[i] {
+				t.Fatalf("%s: same source, different outputs at %d", name, i)
+			}
+			differ = differ || a[i] != c[i]
+		}
+		if !differ {
+			t.Fatalf("%s: different sources, same outputs", name)
+		}
+	}
+	// Nonces that only differ by their length give distinct streams, which
+	// also differ from the expansions of the seed.
+	nonces := [][]byte{nil, {0}, {0, 0}, {0, 0, 0, 0, 0, 0}, {1}}
+	streams := make([][]byte, len(nonces))
+	for i, nonce := range nonces {
+		streams[i] = make([]byte, 32)
+		if _, err := io.ReadFull(negacyclic.NewSeededSource(seed, nonce), streams[i]); err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < i; j++ {
+			if bytes.Equal(streams[i], streams[j]) {
+				t.Fatalf("nonces %v and %v give the same stream", nonces[i], nonces[j])
+			}
+		}
+		sampled := negacyclic.PolynomialFromSlice(negacyclic.UniformModFrom(n, q, negacyclic.NewSeededSource(seed, nonce)))
+		if countEqual(sampled, negacyclic.UniformFromSeed(seed, n, q)) > 4 ||
+			countEqual(sampled, negacyclic.UniformEntryFromSeed(seed, 0, 0, n, q)) > 4 {
+			t.Fatalf("nonce %v gives the stream of a seed expansion", nonce)
+		}
+	}
+}
+
+// testDiscreteGaussian compares the histogram of the CDT sampler with the
+// exact truncated pmf, with a chi-square test.
+func testDiscreteGaussian(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("gauss"))
+	for _, sigma := range []float64{0.8, 3.2, 19.2} {
+		t.Run(fmt.Sprintf("σ=%g", sigma), func(t *testing.T) {
+			tailCut := 6.
+			v := negacyclic.DiscreteGaussian(1<<17, sigma, tailCut, src)
+			counts := make(map[int]int)
+			for _, x := range v.Coeffs {
+				counts[x]++
+			}
+			bound := int(math.Ceil(sigma * tailCut))
+			pmf := make(map[int]float64)
+			for x := -bound; x <= bound; x++ {
+				pmf[x] = math.Exp(-float64(x*x) / (2 * sigma * sigma))
+			}
+			chiSquare(t, counts, pmf)
+		})
+	}
+}
+
+// testCDTTail checks the far tail of the CDT, whose entries differ from 2^63
+// by less than 2^10 and are lost in double precision, by feeding the sampler
+// values right below them. The entries of σ = 3.2 are 2^63 - 857, 2^63 - 58
+// and 2^63 - 4 for |x| = 26, 27 and 28, and 2^63 beyond.
+func testCDTTail(t *testing.T) {
+	g := negacyclic.NewGaussianCDT(3.2, 12)
+	for _, tc := range []struct {
+		below uint64
+		want  int
+	}{{858, 26}, {59, 27}, {5, 28}, {1, 29}} {
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], 1<<63-tc.below)
+		if x := g.Sample(bytes.NewReader(buf[:])); x != tc.want {
+			t.Fatalf("2^63 - %d: got %d, want %d", tc.below, x, tc.want)
+		}
+	}
+}
+
+// testCBD compares the histogram of CBD_η with the binomial pmf
+// C(2η, η+x)/2^{2η}, with a chi-square test.
+func testCBD(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	for _, eta := range []int{1, 2, 3, 5, 16} {
+		t.Run(fmt.Sprintf("η=%d", eta), func(t *testing.T) {
+			v := negacyclic.CBDFromSeed(1<<16, eta, seed, []byte{byte(eta)})
+			counts := make(map[int]int)
+			for _, x := range v.Coeffs {
+				counts[x]++
+			}
+			pmf := make(map[int]float64)
+			for x := -eta; x <= eta; x++ {
+				binomial := new(big.Int).Binomial(int64(2*eta), int64(eta+x))
+				pmf[x], _ = new(big.Float).SetInt(binomial).Float64()
+			}
+			chiSquare(t, counts, pmf)
+		})
+	}
+}
+
+// testCBDBits checks the order in which the bits are consumed.
+func testCBDBits(t *testing.T) {
+	// For η = 2, each nibble holds a_0, a_1, b_0, b_1 from the low bit.
+	stream := []byte{0x0f, 0x03, 0x0c, 0x41}
+	v := negacyclic.CBD(8, 2, bytes.NewReader(stream))
+	checkVectorsEqual(t, negacyclic.VectorFromSlice([]int{0, 0, 2, 0, -2, 0, 1, -1}), v)
+	// For η = 3, coefficients straddle bytes.
+	v = negacyclic.CBD(4, 3, bytes.NewReader([]byte{0x07, 0x7e, 0xe0}))
+	checkVectorsEqual(t, negacyclic.VectorFromSlice([]int{3, -3, 3, -3}), v)
+	// The lane-parallel sums match the bit-by-bit definition.
+	stream = make([]byte, 2*16*37/8)
+	rand.Read(stream)
+	for eta := 1; eta <= 16; eta++ {
+		expected := negacyclic.NewVector(37)
+		for i := range expected.Coeffs {
+			for j := 0; j < 2*eta; j++ {
+				pos := 2*eta*i + j
+				bit := int(stream[pos/8] >> (pos % 8) & 1)
+				if j < eta {
+					expected.Coeffs[i] += bit
+				} else {
+					expected.Coeffs[i] -= bit
+				}
+			}
+		}
+		checkVectorsEqual(t, expected, negacyclic.CBD(37, eta, bytes.NewReader(stream)))
+	}
+}
+
+func testCBDSeed(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	seed[0] = 7
+	a := negacyclic.CBDFromSeed(256, 2, seed, []byte{0})
+	checkVectorsEqual(t, a, negacyclic.CBDFromSeed(256, 2, seed, []byte{0}))
+	checkVectorsEqual(t, a, negacyclic.CBD(256, 2, negacyclic.NewSeededSource(seed, []byte{0})))
+	b := negacyclic.CBDFromSeed(256, 2, seed, []byte{1})
+	if countEqualInts(a.Coeffs, b.Coeffs) == 256 {
+		t.Fatal("different nonces give the same output")
+	}
+}
+
+func countEqualInts(a, b []int) int {
+	count := 0
+	for i := range a {
+		if a[i] == b[i] {
+			count++
+		}
+	}
+	return count
+}
+
+func testTernaryHWT(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("hwt"))
+	n, h := 64, 16
+	trials := 4096
+	positions := make(map[int]int)
+	plus, minus := 0, 0
+	for i := 0; i < trials; i++ {
+		v := negacyclic.TernaryHWT(n, h, src)
+		if weight := negacyclic.HammingWeight(v.Coeffs); weight != h {
+			t.Fatalf("expected weight %d, got %d", h, weight)
+		}
+		for j, x := range v.Coeffs {
+			switch x {
+			case 1:
+				plus++
+			case -1:
+				minus++
+			}
+			if x != 0 {
+				positions[j]++
+			}
+		}
+	}
+	// Signs are balanced: plus - minus has standard deviation sqrt(h·trials).
+	if diff := math.Abs(float64(plus - minus)); diff > 5*math.Sqrt(float64(h*trials)) {
+		t.Fatalf("unbalanced signs: %d ones and %d minus ones", plus, minus)
+	}
+	// The support is uniform.
+	uniform := make(map[int]float64)
+	for j := 0; j < n; j++ {
+		uniform[j] = 1
+	}
+	chiSquare(t, positions, uniform)
+	// The HWT wrapper returns both signs.
+	vec, err := negacyclic.HWT(512, 256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[int]int{}
+	for _, x := range vec {
+		counts[x]++
+	}
+	if counts[1] == 0 || counts[-1] == 0 || counts[1]+counts[-1] != 256 {
+		t.Fatalf("unexpected counts %v", counts)
+	}
+	if _, err := negacyclic.HWT(16, -1); err == nil {
+		t.Fatal("expected an error for a negative hamming weight")
+	}
+}
+
+func testTernaryCounts(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("counts"))
+	for _, c := range [][3]int{{509, 254, 254}, {701, 100, 99}, {16, 16, 0}, {16, 0, 0}} {
+		v := negacyclic.TernaryCounts(c[0], c[1], c[2], src)
+		counts := map[int]int{}
+		for _, x := range v.Coeffs {
+			counts[x]++
+		}
+		if v.Len() != c[0] || counts[1] != c[1] || counts[-1] != c[2] {
+			t.Fatalf("T(%d, %d) of dimension %d: got counts %v", c[1], c[2], c[0], counts)
+		}
+	}
+}
+
+func testTernaryIID(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("iid"))
+	n := 1 << 16
+	for _, rho := range []float64{0, 0.1, 2. / 3, 1} {
+		counts := map[int]float64{}
+		for _, x := range negacyclic.TernaryIID(n, rho, src).Coeffs {
+			counts[x]++
+		}
+		for x, p := range map[int]float64{1: rho / 2, 0: 1 - rho, -1: rho / 2} {
+			expected := float64(n) * p
+			if math.Abs(counts[x]-expected) > 5*math.Sqrt(expected*(1-p))+1e-9 {
+				t.Fatalf("ρ = %g: %g coefficients equal to %d, expected %g", rho, counts[x], x, expected)
+			}
+		}
+	}
+}
+
+// testBigGaussian checks the mean, the variance, the tails and the parity of
+// large-σ samples.
+func testBigGaussian(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("big"))
+	n := 1 << 14
+	for _, sigma := range []float64{10, 1000, math.Exp2(40), math.Exp2(80)} {
+		g := negacyclic.NewBigGaussian(sigma, 6)
+		p := g.SamplePolynomial(n, src)
+		sum, sumSquares := new(big.Float), new(big.Float)
+		beyond, odd := 0, 0
+		threshold := new(big.Float).SetFloat64(3 * sigma)
+		for _, x := range p.Coeffs {
+			if x.CmpAbs(g.Bound()) > 0 {
+				t.Fatalf("σ = %g: sample %d beyond the bound %d", sigma, x, g.Bound())
+			}
+			f := new(big.Float).SetInt(x)
+			if new(big.Float).Abs(f).Cmp(threshold) > 0 {
+				beyond++
+			}
+			sum.Add(sum, f)
+			sumSquares.Add(sumSquares, new(big.Float).Mul(f, f))
+			odd += int(x.Bit(0))
+		}
+		mean, _ := sum.Float64()
+		mean /= float64(n)
+		variance, _ := sumSquares.Float64()
+		variance /= float64(n)
+		if math.Abs(mean) > 5*sigma/math.Sqrt(float64(n)) {
+			t.Fatalf("σ = %g: mean %g", sigma, mean)
+		}
+		// The sample variance has relative deviation sqrt(2/n) < 1.2%.
+		if math.Abs(variance/(sigma*sigma)-1) > 0.06 {
+			t.Fatalf("σ = %g: variance %g, expected %g", sigma, variance, sigma*sigma)
+		}
+		// P(|x| > 3σ) ≈ 0.0027.
+		if expected := 0.0027 * float64(n); math.Abs(float64(beyond)-expected) > 5*math.Sqrt(expected) {
+			t.Fatalf("σ = %g: %d samples beyond 3σ, expected %g", sigma, beyond, expected)
+		}
+		if math.Abs(float64(odd)-float64(n)/2) > 5*math.Sqrt(float64(n)/4) {
+			t.Fatalf("σ = %g: %d odd samples out of %d", sigma, odd, n)
+		}
+	}
+}
+
+// testBigGaussianResidues checks the fine structure of large-σ samples: for
+// m much smaller than σ, x mod m is uniform up to exp(-2π²σ²/m²).
+func testBigGaussianResidues(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("resid"))
+	m := 1024
+	pmf := make(map[int]float64)
+	for x := 0; x < m; x++ {
+		pmf[x] = 1
+	}
+	for _, sigma := range []float64{1e4, math.Exp2(20), math.Exp2(40), math.Exp2(80)} {
+		t.Run(fmt.Sprintf("σ=%g", sigma), func(t *testing.T) {
+			g := negacyclic.NewBigGaussian(sigma, 6)
+			counts := make(map[int]int)
+			residue := new(big.Int)
+			for i := 0; i < 32*m; i++ {
+				residue.Mod(g.Sample(src), big.NewInt(int64(m)))
+				counts[int(residue.Int64())]++
+			}
+			chiSquare(t, counts, pmf)
+		})
+	}
+}
+
+// testCenteredGaussian compares the histogram of D_{Z,σ,c} with the exact pmf,
+// with a chi-square test.
+func testCenteredGaussian(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("center"))
+	samples := 1 << 16
+	for _, sigma := range []float64{1.5, 3.2, 100} {
+		g := negacyclic.NewCenteredGaussian(sigma, 6)
+		for _, c := range []float64{0, 0.5, 0.3, -2.7, 1e6 + 0.25} {
+			t.Run(fmt.Sprintf("σ=%g,c=%g", sigma, c), func(t *testing.T) {
+				centers := make([]float64, samples)
+				for i := range centers {
+					centers[i] = c
+				}
+				counts := make(map[int]int)
+				for _, x := range g.SampleVector(centers, src).Coeffs {
+					counts[x]++
+				}
+				pmf := make(map[int]float64)
+				for x := int(math.Floor(c - 12*sigma)); x <= int(math.Ceil(c+12*sigma)); x++ {
+					pmf[x] = math.Exp(-(float64(x) - c) * (float64(x) - c) / (2 * sigma * sigma))
+				}
+				chiSquare(t, counts, pmf)
+			})
+		}
+	}
+}
+
+func testCenteredGaussianBig(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("cbig"))
+	g := negacyclic.NewCenteredGaussian(3.2, 6)
+	offset := new(big.Int).Lsh(big.NewInt(1), 70)
+	n := 1 << 12
+	for _, c := range []float64{0.5, -0.25} {
+		center := new(big.Float).SetPrec(128).SetInt(offset)
+		center.Add(center, big.NewFloat(c))
+		if c < 0 {
+			center.Neg(center)
+		}
+		if center.IsInt() {
+			t.Fatalf("c = %v: expected a fractional center", center)
+		}
+		centers := make([]*big.Float, n)
+		for i := range centers {
+			centers[i] = center
+		}
+		sum := 0.
+		for _, x := range g.SamplePolynomial(centers, src).Coeffs {
+			diff, _ := new(big.Float).Sub(new(big.Float).SetInt(x), center).Float64()
+			if math.Abs(diff) > 6*math.Sqrt2*3.2+1 {
+				t.Fatalf("c = %v: sample %d too far from the center", center, x)
+			}
+			sum += diff
+		}
+		if mean := sum / float64(n); math.Abs(mean) > 5*3.2/math.Sqrt(float64(n)) {
+			t.Fatalf("c = %v: mean offset %g", center, mean)
+		}
+	}
+}
+
+// testCenteredGaussianBigSigma checks the mean and the variance of samples for
+// a σ and a center that are not float64 values.
+func testCenteredGaussianBigSigma(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("csigma"))
+	// σ = 2^100 + 2^20 and c = 2^120 + 1/2.
+	sigma := new(big.Float).SetPrec(128).SetMantExp(big.NewFloat(1), 100)
+	sigma.Add(sigma, big.NewFloat(1<<20))
+	center := new(big.Float).SetPrec(128).SetMantExp(big.NewFloat(1), 120)
+	center.Add(center, big.NewFloat(.5))
+	g := negacyclic.NewCenteredGaussianBig(sigma, 6)
+	if g.Sigma.Cmp(sigma) != 0 {
+		t.Fatalf("σ = %v, expected %v", g.Sigma, sigma)
+	}
+	n := 1 << 12
+	centers := make([]*big.Float, n)
+	for i := range centers {
+		centers[i] = center
+	}
+	s, _ := sigma.Float64()
+	sum, sumSquares := 0., 0.
+	for _, x := range g.SamplePolynomial(centers, src).Coeffs {
+		diff, _ := new(big.Float).Sub(new(big.Float).SetInt(x), center).Float64()
+		sum += diff
+		sumSquares += diff * diff
+	}
+	if mean := sum / float64(n); math.Abs(mean) > 5*s/math.Sqrt(float64(n)) {
+		t.Fatalf("mean offset %g", mean)
+	}
+	// The sample variance has relative deviation sqrt(2/n) ≈ 2.2%.
+	if variance := sumSquares / float64(n); math.Abs(variance/(s*s)-1) > 0.12 {
+		t.Fatalf("variance %g, expected %g", variance, s*s)
+	}
+}
+
+func testCosetGaussian(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	src := negacyclic.NewSeededSource(seed, []byte("coset"))
+	g := negacyclic.NewCenteredGaussian(4, 6)
+	n, q := 1<<14, 16
+	coset := negacyclic.NewVector(n)
+	centers := make([]float64, n)
+	for i := range centers {
+		coset.Coeffs[i] = i % q
+		centers[i] = 100.5
+	}
+	sum, sumSquares := 0., 0.
+	for i, x := range g.SampleCoset(coset, q, centers, src).Coeffs {
+		if (x-coset.Coeffs[i])%q != 0 {
+			t.Fatalf("sample %d not in the coset %d + %dZ", x, coset.Coeffs[i], q)
+		}
+		d := float64(x) - centers[i]
+		sum += d
+		sumSquares += d * d
+	}
+	// The parameter over the coset is qσ = 64.
+	sigma := float64(q) * 4
+	if mean := sum / float64(n); math.Abs(mean) > 5*sigma/math.Sqrt(float64(n)) {
+		t.Fatalf("mean offset %g", mean)
+	}
+	if variance := sumSquares / float64(n); math.Abs(variance/(sigma*sigma)-1) > 0.06 {
+		t.Fatalf("variance %g, expected %g", variance, sigma*sigma)
+	}
+}
+
+// testUniformBounded checks the range and uniformity of the bounded samples,
+// with a chi-square test, and the seeded expansion.
+func testUniformBounded(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	n := 1 << 16
+	for _, gamma := range []int{1, 5, 3 << 10} {
+		v := negacyclic.UniformBoundedFromSeed(n, gamma, seed, []byte{0})
+		checkVectorsEqual(t, v, negacyclic.UniformBounded(n, gamma, negacyclic.NewSeededSource(seed, []byte{0})))
+		counts := make(map[int]int)
+		for _, x := range v.Coeffs {
+			counts[x]++
+		}
+		pmf := make(map[int]float64)
+		for x := -gamma + 1; x <= gamma; x++ {
+			pmf[x] = 1
+		}
+		chiSquare(t, counts, pmf)
+	}
+	a := negacyclic.UniformBoundedFromSeed(256, 1<<17, seed, []byte{1})
+	b := negacyclic.UniformBoundedFromSeed(256, 1<<17, seed, []byte{2})
+	if countEqualInts(a.Coeffs, b.Coeffs) > 4 {
+		t.Fatal("different nonces give similar outputs")
+	}
+}
+
+func testUniformBoundedBig(t *testing.T) {
+	seed := make([]byte, negacyclic.SeedSize)
+	n := 1 << 12
+	gamma := new(big.Int).Lsh(big.NewInt(1), 100)
+	p := negacyclic.UniformBoundedBigFromSeed(n, gamma, seed, []byte("big"))
+	checkPolynomialsEqual(t, p, negacyclic.UniformBoundedBig(n, gamma, negacyclic.NewSeededSource(seed, []byte("big"))))
+	low := new(big.Int).Neg(gamma)
+	negatives := 0
+	for _, x := range p.Coeffs {
+		if x.Cmp(low) <= 0 || x.Cmp(gamma) > 0 {
+			t.Fatalf("sample %d out of range", x)
+		}
+		if x.Sign() < 0 {
+			negatives++
+		}
+	}
+	if math.Abs(float64(negatives)-float64(n)/2) > 5*math.Sqrt(float64(n)/4) {
+		t.Fatalf("%d negative samples out of %d", negatives, n)
+	}
+	// γ = 1 only outputs 0 and 1.
+	for _, x := range negacyclic.UniformBoundedBig(64, big.NewInt(1), negacyclic.DefaultSource).Coeffs {
+		if x.Sign() < 0 || x.Cmp(big.NewInt(1)) > 0 {
+			t.Fatalf("sample %d out of range", x)
+		}
+	}
+}
+
+// chiSquare runs a chi-square goodness-of-fit test of the histogram counts
+// against the pmf, given up to a constant factor. Bins with fewer than 5
+// expected samples are merged into one, and the test fails when the statistic
+// exceeds its mean df by more than 5 standard deviations sqrt(2df), for df the
+// number of bins minus one.
+func chiSquare(t *testing.T, counts map[int]int, pmf map[int]float64) {
+	t.Helper()
+	samples, total := 0, 0.
+	for x, count := range counts {
+		if _, ok := pmf[x]; !ok {
+			t.Fatalf("sample %d outside the support", x)
+		}
+		samples += count
+	}
+	for _, p := range pmf {
+		total += p
+	}
+	chi2, bins := 0., 0
+	mergedExpected, mergedCount := 0., 0.
+	for x, p := range pmf {
+		expected := float64(samples) * p / total
+		count := float64(counts[x])
+		if expected < 5 {
+			mergedExpected += expected
+			mergedCount += count
+			continue
+		}
+		chi2 += (count - expected) * (count - expected) / expected
+		bins++
+	}
+	if mergedExpected > 0 {
+		chi2 += (mergedCount - mergedExpected) * (mergedCount - mergedExpected) / mergedExpected
+		bins++
+	}
+	df := float64(bins - 1)
+	if chi2 > df+5*math.Sqrt(2*df) {
+		t.Fatalf("chi-square statistic %f for %g degrees of freedom", chi2, df)
+	}
+}
@@ -0,0 +1,94 @@
+package negacyclic
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"math/big"
+)
+
+// SeedSize is the length in bytes of the seeds expanded by UniformFromSeed and
+// UniformMatrixFromSeed, the key length of AES-256.
+const SeedSize = 32
+
+// UniformFromSeed deterministically expands a 32-byte seed into a polynomial
+// of given degree with uniform coefficients in Z/qZ. The coefficients are
+// obtained by rejection sampling on the AES-256-CTR keystream of the seed, so
+// that a public uniform polynomial can be transmitted as its seed.
+func UniformFromSeed(seed []byte, deg int, q *big.Int) *Polynomial {
+	return PolynomialFromSlice(uniformSlice(newSeedStream(seed, seedTagUniform, nil), deg, q))
+}
+
+// UniformMatrixFromSeed deterministically expands a 32-byte seed into a
+// rows×cols matrix of uniform polynomials in Z/qZ, as used by module
+// schemes. The entry (i, j) only depends on the seed, i and j, and is
+// independent from the other entries and from UniformFromSeed.
+func UniformMatrixFromSeed(seed []byte, rows, cols, deg int, q *big.Int) [][]*Polynomial {
+	matrix := make([][]*Polynomial, rows)
+	for i := range matrix {
+		matrix[i] = make([]*Polynomial, cols)
+		for j := range matrix[i] {
+			matrix[i][j] = UniformEntryFromSeed(seed, i, j, deg, q)
+		}
+	}
+	return matrix
+}
+
+// UniformEntryFromSeed returns the entry (i, j) of UniformMatrixFromSeed,
+// without expanding the rest of the matrix.
+func UniformEntryFromSeed(seed []byte, i, j, deg int, q *big.Int) *Polynomial {
+	if i < 0 || j < 0 || i >= 1<<24 || j >= 1<<24 {
+		panic("matrix indices out of range")
+	}
+	// i and j are encoded on 3 bytes each, so that each entry uses its own
+	// keystream.
+	data := []byte{byte(i >> 16), byte(i >> 8), byte(i), byte(j >> 16), byte(j >> 8), byte(j)}
+	return PolynomialFromSlice(uniformSlice(newSeedStream(seed, seedTagMatrix, data), deg, q))
+}
+
+//
+// Internal
+//
+
+// Tags of the keystreams derived from a seed, which separate the streams of
+// the different uses of a seed.
+const (
+	seedTagUniform byte = 1 + iota
+	seedTagMatrix
+	seedTagSource
+)
+
+// seedStream is the AES-256-CTR keystream of a seed, as an io.Reader.
+type seedStream struct {
+	stream cipher.Stream
+}
+
+// newSeedStream returns the keystream of AES-256-CTR keyed by the seed, with
+// the IV tag || data || 0..., where data is at most 7 bytes long. The last 8
+// bytes of the IV are thus a counter of its own, which cannot carry into the
+// tag or the data: two streams with distinct tags, or with the same tag and
+// distinct data of the same length, never share a block.
+func newSeedStream(seed []byte, tag byte, data []byte) *seedStream {
+	if len(seed) != SeedSize {
+		panic("seed must be 32 bytes long")
+	}
+	if len(data) > 7 {
+		panic("seed stream data must be at most 7 bytes long")
+	}
+	block, err := aes.NewCipher(seed)
+	if err != nil {
+		panic(err)
+	}
+	iv := make([]byte, aes.BlockSize)
+	iv[0] = tag
+	copy(iv[1:], data)
+	return &seedStream{stream: cipher.NewCTR(block, iv)}
+}
+
+// Read fills p with keystream bytes. It never fails.
+func (s *seedStream) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	s.stream.XORKeyStream(p, p)
+	return len(p), nil
+}
@@ -0,0 +1,86 @@
+package negacyclic_test
+
+import (
+	"math/big"
+	"testing"
+
+	"negacyclic"
+)
+
+func TestUniformFromSeed(t *testing.T) {
+	t.Run("deterministic", testSeedDeterministic)
+	t.Run("matrix", testSeedMatrix)
+	t.Run("distribution", testSeedDistribution)
+}
+
+func testSeedDeterministic(t *testing.T) {
+	n := 256
+	q := negacyclic.RLWEPrime(60, 2*n)
+	seed := make([]byte, negacyclic.SeedSize)
+	a := negacyclic.UniformFromSeed(seed, n, q)
+	checkPolynomialsEqual(t, a, negacyclic.UniformFromSeed(seed, n, q))
+	for _, coeff := range a.Coeffs {
+		if coeff.Sign() < 0 || coeff.Cmp(q) >= 0 {
+			t.Fatalf("coefficient %d out of range", coeff)
+		}
+	}
+	seed[31] = 1
+	if countEqual(a, negacyclic.UniformFromSeed(seed, n, q)) > 2 {
+		t.Fatal("different seeds expand to similar polynomials")
+	}
+}
+
+func testSeedMatrix(t *testing.T) {
+	n, k := 64, 3
+	q := big.NewInt(3329)
+	seed := make([]byte, negacyclic.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	matrix := negacyclic.UniformMatrixFromSeed(seed, k, k, n, q)
+	single := negacyclic.UniformFromSeed(seed, n, q)
+	for i := 0; i < k; i++ {
+		for j := 0; j < k; j++ {
+			checkPolynomialsEqual(t, matrix[i][j], negacyclic.UniformEntryFromSeed(seed, i, j, n, q))
+			if countEqual(matrix[i][j], single) > 4 {
+				t.Fatalf("entry (%d, %d) is similar to the single expansion", i, j)
+			}
+			if i != j && countEqual(matrix[i][j], matrix[j][i]) > 4 {
+				t.Fatalf("entries (%d, %d) and (%d, %d) are similar", i, j, j, i)
+			}
+		}
+	}
+}
+
+// testSeedDistribution checks the counts of each residue with a chi-square
+// statistic, for a modulus just above a power of two so that rejections are
+// frequent.
+func testSeedDistribution(t *testing.T) {
+	n := 1 << 14
+	q := big.NewInt(17)
+	seed := make([]byte, negacyclic.SeedSize)
+	seed[0] = 42
+	counts := make([]float64, 17)
+	for _, coeff := range negacyclic.UniformFromSeed(seed, n, q).Coeffs {
+		counts[coeff.Int64()]++
+	}
+	expected := float64(n) / 17
+	chi2 := 0.
+	for _, c := range counts {
+		chi2 += (c - expected) * (c - expected) / expected
+	}
+	// 16 degrees of freedom: P(χ² > 39.25) = 0.001.
+	if chi2 > 39.25 {
+		t.Fatalf("chi-square statistic %f too large", chi2)
+	}
+}
+
+func countEqual(a, b *negacyclic.Polynomial) int {
+	count := 0
+	for i := range a.Coeffs {
+		if a.Coeffs[i].Cmp(b.Coeffs[i]) == 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,110 @@
+package negacyclic
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"math"
+	"math/big"
+)
+
+// Source is a stream of random bytes, from which every sampler of the package
+// draws its randomness. Samplers are deterministic functions of the bytes
+// read. A Source must not fail: read errors cause a panic.
+type Source interface {
+	io.Reader
+}
+
+// DefaultSource is the cryptographic source used by the samplers that do not
+// take a Source argument.
+var DefaultSource Source = rand.Reader
+
+// NewSeededSource returns the deterministic Source of the AES-256-CTR
+// keystream keyed by the 32-byte seed, for a nonce at most 6 bytes long.
+// Distinct nonces, including nonces that only differ by their length, give
+// independent streams, which are also independent from the expansions of
+// UniformFromSeed and UniformEntryFromSeed.
+func NewSeededSource(seed, nonce []byte) Source {
+	if len(nonce) > 6 {
+		panic("nonce must be at most 6 bytes long")
+	}
+	data := append([]byte{byte(len(nonce))}, nonce...)
+	return newSeedStream(seed, seedTagSource, data)
+}
+
+//
+// Internal
+//
+
+// readFull fills buf from src, and panics on failure.
+func readFull(src Source, buf []byte) {
+	if _, err := io.ReadFull(src, buf); err != nil {
+		panic("fatal entropy error:" + err.Error())
+	}
+}
+
+// randUint64 returns 64 uniform bits read from src.
+func randUint64(src Source) uint64 {
+	var buf [8]byte
+	readFull(src, buf[:])
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// randIntn returns a uniform integer in [0, n), by rejection of the values
+// beyond the largest multiple of n.
+func randIntn(src Source, n int) int {
+	if n <= 0 {
+		panic("randIntn expects n > 0")
+	}
+	bound := uint64(n)
+	// limit = 2^64 - (2^64 mod n) is a multiple of n.
+	rem := (math.MaxUint64%bound + 1) % bound
+	for {
+		x := randUint64(src)
+		if rem == 0 || x < -rem {
+			return int(x % bound)
+		}
+	}
+}
+
+// randFloat64 returns a uniform float64 in [0, 1), with 53 bits of precision.
+func randFloat64(src Source) float64 {
+	return float64(randUint64(src)>>11) / (1 << 53)
+}
+
+// normFloat64 returns a sample of the standard normal distribution, with the
+// Box-Muller transform.
+func normFloat64(src Source) float64 {
+	u := 1 - randFloat64(src) // in (0, 1]
+	v := randFloat64(src)
+	return math.Sqrt(-2*math.Log(u)) * math.Cos(2*math.Pi*v)
+}
+
+// randBigIntn returns a uniform integer in [0, q), by rejection: each
+// candidate is read big-endian from the byte length of q, with the excess high
+// bits cleared, and is accepted if less than q.
+func randBigIntn(src Source, q *big.Int, buf []byte) *big.Int {
+	bitLen := q.BitLen()
+	mask := byte(0xff >> (8*len(buf) - bitLen))
+	x := new(big.Int)
+	for {
+		readFull(src, buf)
+		buf[0] &= mask
+		if x.SetBytes(buf).Cmp(q) < 0 {
+			return x
+		}
+	}
+}
+
+// uniformSlice returns deg uniform integers in [0, q).
+func uniformSlice(src Source, deg int, q *big.Int) []*big.Int {
+	if q.Sign() <= 0 {
+		panic("modulus must be positive")
+	}
+	buf := make([]byte, (q.BitLen()+7)/8)
+	res := make([]*big.Int, deg)
+	for i := range res {
+		res[i] = randBigIntn(src, q, buf)
+	}
+	return res
+}
@@ -0,0 +1,71 @@
+package negacyclic
+
+// TernaryHWT returns a uniformly random vector of {0, ±1}^dim with exactly
+// `hamming` non-zero coefficients, of independent uniform signs, drawn from
+// src. The support is chosen by a partial Fisher–Yates shuffle.
+func TernaryHWT(dim, hamming int, src Source) *Vector {
+	if hamming < 0 || hamming > dim {
+		panic("impossible hamming weight")
+	}
+	v := NewVector(dim)
+	for _, index := range randomSubset(dim, hamming, src) {
+		v.Coeffs[index] = 1 - 2*randIntn(src, 2)
+	}
+	return v
+}
+
+// TernaryCounts returns a uniformly random vector of {0, ±1}^dim with exactly
+// `plus` coefficients equal to 1 and `minus` coefficients equal to -1, that
+// is, a sample of NTRU's T(d1, d2) for d1 = plus and d2 = minus, drawn from
+// src.
+func TernaryCounts(dim, plus, minus int, src Source) *Vector {
+	if plus < 0 || minus < 0 || plus+minus > dim {
+		panic("impossible ternary counts")
+	}
+	v := NewVector(dim)
+	for i, index := range randomSubset(dim, plus+minus, src) {
+		if i < plus {
+			v.Coeffs[index] = 1
+		} else {
+			v.Coeffs[index] = -1
+		}
+	}
+	return v
+}
+
+// TernaryIID returns a vector of {0, ±1}^dim whose coefficients are
+// independently 1, 0 or -1 with probabilities ρ/2, 1-ρ and ρ/2, drawn from
+// src. The probability ρ is rounded to a multiple of 2^-53.
+func TernaryIID(dim int, rho float64, src Source) *Vector {
+	if rho < 0 || rho > 1 {
+		panic("ρ must lie in [0, 1]")
+	}
+	threshold := uint64(rho * (1 << 53))
+	v := NewVector(dim)
+	for i := range v.Coeffs {
+		r := randUint64(src)
+		// The low 53 bits decide the support, the top bit the sign.
+		if r&(1<<53-1) < threshold {
+			v.Coeffs[i] = 1 - 2*int(r>>63)
+		}
+	}
+	return v
+}
+
+//
+// Internal
+//
+
+// randomSubset returns k distinct uniform indices in [0, n), in uniformly
+// random order, with a partial Fisher–Yates shuffle.
+func randomSubset(n, k int, src Source) []int {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+	for i := 0; i < k; i++ {
+		j := i + randIntn(src, n-i)
+		indices[i], indices[j] = indices[j], indices[i]
+	}
+	return indices[:k]
+}
@@ -0,0 +1,76 @@
+package tfhe
+
+import (
+	"math/bits"
+
+	"negacyclic"
+	"negacyclic/rlwe"
+)
+
+// BootstrappingKey is the list of RGSW encryptions of the coefficients of an
+// LWE secret key under an RLWE secret key.
+type BootstrappingKey struct {
+	Keys []*RGSWCiphertext
+}
+
+// GenBootstrappingKey returns the bootstrapping key of the LWE secret key lwe
+// under the RLWE secret key sk, drawing its randomness from src.
+func (params *Parameters) GenBootstrappingKey(lwe *LWESecretKey, sk *rlwe.SecretKey, src negacyclic.Source) *BootstrappingKey {
+	bsk := &BootstrappingKey{Keys: make([]*RGSWCiphertext, params.LWEDim)}
+	for i, bit := range lwe.S.Coeffs {
+		mu := negacyclic.NewPolynomial(params.N)
+		mu.Coeffs[0].SetInt64(int64(bit))
+		bsk.Keys[i] = params.EncryptRGSW(sk, mu, src)
+	}
+	return bsk
+}
+
+// LookUpTable returns the test vector Σ f(j)·X^j, for j = 0, ..., N-1, with
+// f(j) reduced modulo q.
+func (params *Parameters) LookUpTable(f func(int) int) *negacyclic.Polynomial {
+	tv := negacyclic.NewPolynomial(params.N)
+	for j, coeff := range tv.Coeffs {
+		coeff.SetInt64(int64(params.mod(f(j))))
+	}
+	return tv
+}
+
+// BlindRotate returns an RLWE ciphertext of phase X^{-φ}·tv, where φ is the
+// phase of ct switched to the modulus 2N, that is, φ ≈ 2N/q·(b + <a, s>)
+// mod 2N. The constant coefficient of this phase is tv_φ for φ < N, and
+// -tv_{φ-N} otherwise: the LWE ciphertext is mapped to an encryption of the
+// look-up table tv evaluated at its phase.
+func (params *Parameters) BlindRotate(ct *LWECiphertext, bsk *BootstrappingKey, tv *negacyclic.Polynomial) *rlwe.Ciphertext {
+	if ct.A.Len() != len(bsk.Keys) {
+		panic("LWE ciphertext of unexpected dimension")
+	}
+	acc := &rlwe.Ciphertext{
+		C0: negacyclic.MulMonomial(tv, -params.switchModulus(ct.B)),
+		C1: negacyclic.NewPolynomial(params.N),
+	}
+	params.reduce(acc.C0)
+	for i, a := range ct.A.Coeffs {
+		k := params.switchModulus(a)
+		if k == 0 {
+			continue
+		}
+		rotated := &rlwe.Ciphertext{
+			C0: negacyclic.MulMonomial(acc.C0, -k),
+			C1: negacyclic.MulMonomial(acc.C1, -k),
+		}
+		params.reduce(rotated.C0)
+		params.reduce(rotated.C1)
+		acc = params.CMux(bsk.Keys[i], acc, rotated)
+	}
+	return acc
+}
+
+// switchModulus returns ⌊2N·x/q⌉ mod 2N.
+func (params *Parameters) switchModulus(x int) int {
+	twoN := 2 * params.N
+	shift := params.LogQ - (bits.Len(uint(twoN)) - 1)
+	if shift <= 0 {
+		return (x << uint(-shift)) % twoN
+	}
+	return ((x + 1<<uint(shift-1)) >> uint(shift)) % twoN
+}
@@ -0,0 +1,92 @@
+package tfhe
+
+import (
+	"math/big"
+
+	"negacyclic"
+	"negacyclic/rlwe"
+)
+
+// RGSWCiphertext is an encryption of a small polynomial μ, made of 2l RLWE
+// ciphertexts: rows i and l+i encrypt zero, plus μ·g_i added to c0 and c1
+// respectively, where g_i = q/B^{i+1}.
+type RGSWCiphertext struct {
+	Rows []*rlwe.Ciphertext
+	// fft holds the evaluations of the rows, computed once for all external
+	// products.
+	fft [][2][]complex128
+}
+
+// EncryptRGSW returns an RGSW encryption of the small polynomial mu, drawing
+// its randomness from src.
+func (params *Parameters) EncryptRGSW(sk *rlwe.SecretKey, mu *negacyclic.Polynomial, src negacyclic.Source) *RGSWCiphertext {
+	l := params.Levels
+	ct := &RGSWCiphertext{Rows: make([]*rlwe.Ciphertext, 2*l)}
+	for i := 0; i < l; i++ {
+		gi := new(big.Int).Lsh(big.NewInt(1), uint(params.LogQ-params.LogBase*(i+1)))
+		shifted := mu.Copy()
+		shifted.Scale(gi)
+		ct.Rows[i] = params.EncryptRLWE(sk, negacyclic.NewPolynomial(params.N), src)
+		ct.Rows[i].C0 = negacyclic.Add(ct.Rows[i].C0, shifted)
+		params.reduce(ct.Rows[i].C0)
+		ct.Rows[l+i] = params.EncryptRLWE(sk, negacyclic.NewPolynomial(params.N), src)
+		ct.Rows[l+i].C1 = negacyclic.Add(ct.Rows[l+i].C1, shifted)
+		params.reduce(ct.Rows[l+i].C1)
+	}
+	return ct
+}
+
+// ExternalProduct returns an RLWE ciphertext whose phase is μ times the phase
+// of ct, up to noise, where μ is the message of the RGSW ciphertext c. It
+// decomposes c0 and c1 along the gadget vector, and returns the sum of the
+// rows of c weighted by the digits.
+func (params *Parameters) ExternalProduct(c *RGSWCiphertext, ct *rlwe.Ciphertext) *rlwe.Ciphertext {
+	if c.fft == nil {
+		c.fft = make([][2][]complex128, len(c.Rows))
+		for i, row := range c.Rows {
+			c.fft[i] = [2][]complex128{params.fft(row.C0), params.fft(row.C1)}
+		}
+	}
+	l := params.Levels
+	acc0 := make([]complex128, params.N/2)
+	acc1 := make([]complex128, params.N/2)
+	for k, p := range []*negacyclic.Polynomial{ct.C0, ct.C1} {
+		for i, digit := range params.gadgetDecompose(p) {
+			d := params.fft(digit)
+			row := c.fft[k*l+i]
+			for j := range d {
+				acc0[j] += d[j] * row[0][j]
+				acc1[j] += d[j] * row[1][j]
+			}
+		}
+	}
+	return &rlwe.Ciphertext{C0: params.inverseFFT(acc0), C1: params.inverseFFT(acc1)}
+}
+
+// CMux returns an encryption of ct0 if c encrypts 0, and of ct1 if c encrypts
+// 1, as ct0 + c ⊡ (ct1 - ct0).
+func (params *Parameters) CMux(c *RGSWCiphertext, ct0, ct1 *rlwe.Ciphertext) *rlwe.Ciphertext {
+	diff := &rlwe.Ciphertext{C0: negacyclic.Sub(ct1.C0, ct0.C0), C1: negacyclic.Sub(ct1.C1, ct0.C1)}
+	prod := params.ExternalProduct(c, diff)
+	res := &rlwe.Ciphertext{C0: negacyclic.Add(ct0.C0, prod.C0), C1: negacyclic.Add(ct0.C1, prod.C1)}
+	params.reduce(res.C0)
+	params.reduce(res.C1)
+	return res
+}
+
+// gadgetDecompose returns the signed digits (d_0, ..., d_{l-1}) such that
+// p ≈ Σ d_i·q/B^{i+1} mod q, with coefficients in [-B/2, B/2]. The error of
+// the approximation is at most q/(2B^l) per coefficient.
+func (params *Parameters) gadgetDecompose(p *negacyclic.Polynomial) []*negacyclic.Polynomial {
+	// Round p to a multiple of q/B^l, and decompose the quotient, centered
+	// modulo B^l, in base B.
+	scale := new(big.Int).Lsh(big.NewInt(1), uint(params.gadgetBits))
+	rounded := p.Copy().Mod(params.q).ScaleNearest(scale)
+	mod := new(big.Int).Lsh(big.NewInt(1), uint(params.LogBase*params.Levels))
+	digits := negacyclic.Decompose(rounded.Mod(mod), params.base, params.Levels)
+	// The digit of B^j multiplies q/B^{l-j}.
+	for i, j := 0, len(digits)-1; i < j; i, j = i+1, j-1 {
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+	return digits
+}
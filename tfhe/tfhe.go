@@ -0,0 +1,193 @@
+// Package tfhe implements the ring-based bootstrapping of TFHE and FHEW over
+// the negacyclic ring R_q = Z_q[X]/(X^N+1), for a power-of-two modulus
+// q = 2^LogQ.
+//
+// LWE ciphertexts are pairs (a, b) with phase b + <a, s> mod q, for a binary
+// secret s, and RLWE ciphertexts are pairs (c0, c1) with phase c0 + c1·z mod
+// q, for a binary secret z, see package rlwe. RGSW ciphertexts encrypt small
+// polynomials μ as 2l RLWE encryptions of zero, shifted by μ times the gadget
+// vector. Their external product with RLWE ciphertexts multiplies the phase by
+// μ, which allows to select between two RLWE ciphertexts (CMux), and to rotate
+// a test vector by the phase of an LWE ciphertext (BlindRotate).
+//
+// Since q is not a prime, products in R_q are computed with the complex FFT of
+// the canonical embedding, in double precision. Their coefficients reach about
+// N·2^(LogQ+LogBase), and the 53-bit mantissa of float64 keeps the error
+// within a few units of the result as long as q has at most 32 bits, which
+// NewParameters enforces.
+package tfhe
+
+import (
+	"math"
+	"math/big"
+
+	"negacyclic"
+	"negacyclic/rlwe"
+)
+
+// maxLogQ bounds the bit length of the modulus, for products in the complex FFT
+// to stay within a few units of the exact result.
+const maxLogQ = 32
+
+// Parameters defines an instance of the scheme. The gadget vector of RGSW
+// ciphertexts is (q/B, q/B^2, ..., q/B^l), for B = 2^LogBase and l = Levels.
+type Parameters struct {
+	N          int
+	LogQ       int
+	LWEDim     int
+	LogBase    int
+	Levels     int
+	LWEError   rlwe.Distribution
+	RLWEError  rlwe.Distribution
+	q          *big.Int
+	base       *big.Int
+	gadgetBits int
+}
+
+// LWESecretKey is a binary vector s of dimension LWEDim.
+type LWESecretKey struct {
+	S *negacyclic.Vector
+}
+
+// LWECiphertext is a pair (a, b) of Z_q^n × Z_q with phase b + <a, s> mod q.
+type LWECiphertext struct {
+	A *negacyclic.Vector
+	B int
+}
+
+// NewParameters creates and returns the parameters of the scheme for ring
+// degree n, modulus 2^logQ with logQ <= 32, LWE dimension lweDim, gadget base
+// 2^logBase with `levels` levels, and the given LWE and RLWE error
+// distributions.
+func NewParameters(n, logQ, lweDim, logBase, levels int, lweErr, rlweErr rlwe.Distribution) *Parameters {
+	if n < 2 || n&(n-1) != 0 {
+		panic("tfhe expects `n` power of two")
+	}
+	if logQ < 1 || logQ > maxLogQ {
+		panic("tfhe expects a modulus of at most 32 bits")
+	}
+	if logBase < 1 || levels < 1 || logBase*levels > logQ {
+		panic("tfhe expects a gadget vector within the modulus")
+	}
+	params := new(Parameters)
+	params.N = n
+	params.LogQ = logQ
+	params.LWEDim = lweDim
+	params.LogBase = logBase
+	params.Levels = levels
+	params.LWEError = lweErr
+	params.RLWEError = rlweErr
+	params.q = new(big.Int).Lsh(big.NewInt(1), uint(logQ))
+	params.base = new(big.Int).Lsh(big.NewInt(1), uint(logBase))
+	params.gadgetBits = logQ - logBase*levels
+	return params
+}
+
+// Q returns the modulus 2^LogQ.
+func (params *Parameters) Q() *big.Int {
+	return new(big.Int).Set(params.q)
+}
+
+// LWEKeyGen samples and returns a binary LWE secret key, drawn from src.
+func (params *Parameters) LWEKeyGen(src negacyclic.Source) *LWESecretKey {
+	return &LWESecretKey{S: binaryVector(params.LWEDim, src)}
+}
+
+// RLWEKeyGen samples and returns a binary RLWE secret key, drawn from src.
+func (params *Parameters) RLWEKeyGen(src negacyclic.Source) *rlwe.SecretKey {
+	return &rlwe.SecretKey{S: binaryVector(params.N, src)}
+}
+
+// EncryptLWE returns an encryption (a, -<a, s> + e + mu) of the torus element
+// mu in Z_q, where a and e are drawn from src.
+func (params *Parameters) EncryptLWE(sk *LWESecretKey, mu int, src negacyclic.Source) *LWECiphertext {
+	a := negacyclic.NewVector(params.LWEDim)
+	for i, coeff := range negacyclic.UniformModFrom(params.LWEDim, params.q, src) {
+		a.Coeffs[i] = int(coeff.Int64())
+	}
+	e := params.LWEError.Sample(1, src).Coeffs[0]
+	b := params.mod(-dot(a, sk.S) + e + mu)
+	return &LWECiphertext{A: a, B: b}
+}
+
+// LWEPhase returns the phase b + <a, s> mod q of ct, in [0, q).
+func (params *Parameters) LWEPhase(sk *LWESecretKey, ct *LWECiphertext) int {
+	return params.mod(ct.B + dot(ct.A, sk.S))
+}
+
+// EncryptRLWE returns an encryption (-a·z + e + mu, a) of the polynomial mu
+// in R_q, where a and e are drawn from src.
+func (params *Parameters) EncryptRLWE(sk *rlwe.SecretKey, mu *negacyclic.Polynomial, src negacyclic.Source) *rlwe.Ciphertext {
+	a := negacyclic.PolynomialFromSlice(negacyclic.UniformModFrom(params.N, params.q, src))
+	b := params.mul(a, sk.S.Polynomial())
+	b.Negate()
+	b = negacyclic.Add(b, params.RLWEError.Sample(params.N, src))
+	b = negacyclic.Add(b, mu)
+	params.reduce(b)
+	return &rlwe.Ciphertext{C0: b, C1: a}
+}
+
+// RLWEPhase returns the phase c0 + c1·z mod q of ct, with coefficients in
+// [0, q).
+func (params *Parameters) RLWEPhase(sk *rlwe.SecretKey, ct *rlwe.Ciphertext) *negacyclic.Polynomial {
+	phase := negacyclic.Add(params.mul(ct.C1, sk.S.Polynomial()), ct.C0)
+	params.reduce(phase)
+	return phase
+}
+
+// mul returns the product of x and y in R_q, computed with the complex FFT.
+func (params *Parameters) mul(x, y *negacyclic.Polynomial) *negacyclic.Polynomial {
+	fx, fy := params.fft(x), params.fft(y)
+	for i := range fx {
+		fx[i] *= fy[i]
+	}
+	return params.inverseFFT(fx)
+}
+
+// fft returns the evaluations of the representative of p modulo q with
+// coefficients in (-q/2, q/2] at the first N/2 primitive 2N-th roots of
+// unity, which determine p.
+func (params *Parameters) fft(p *negacyclic.Polynomial) []complex128 {
+	return negacyclic.CanonicalEmbedding(p.Copy().Mod(params.q))[:params.N/2]
+}
+
+// inverseFFT returns the polynomial of R_q, with coefficients in [0, q), whose
+// evaluations are given by values, after rounding.
+func (params *Parameters) inverseFFT(values []complex128) *negacyclic.Polynomial {
+	coeffs := negacyclic.InverseCanonicalEmbedding(values)
+	p := negacyclic.NewPolynomial(params.N)
+	for i, coeff := range coeffs {
+		p.Coeffs[i].SetInt64(int64(params.mod(int(math.Round(coeff)))))
+	}
+	return p
+}
+
+func (params *Parameters) reduce(p *negacyclic.Polynomial) {
+	for _, coeff := range p.Coeffs {
+		coeff.Mod(coeff, params.q)
+	}
+}
+
+// mod returns x mod q in [0, q).
+func (params *Parameters) mod(x int) int {
+	return x & (1<<uint(params.LogQ) - 1)
+}
+
+func dot(a, s *negacyclic.Vector) int {
+	if a.Len() != s.Len() {
+		panic("incompatible inner product")
+	}
+	res := 0
+	for i := range a.Coeffs {
+		res += a.Coeffs[i] * s.Coeffs[i]
+	}
+	return res
+}
+
+func binaryVector(n int, src negacyclic.Source) *negacyclic.Vector {
+	v := negacyclic.NewVector(n)
+	for i, coeff := range negacyclic.UniformModFrom(n, big.NewInt(2), src) {
+		v.Coeffs[i] = int(coeff.Int64())
+	}
+	return v
+}
@@ -0,0 +1,132 @@
+package tfhe_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"negacyclic"
+	"negacyclic/rlwe"
+	"negacyclic/tfhe"
+)
+
+func TestTFHE(t *testing.T) {
+	t.Run("lwe", testLWE)
+	t.Run("rlwe", testRLWE)
+	t.Run("external_product", testExternalProduct)
+	t.Run("cmux", testCMux)
+	t.Run("blind_rotate", testBlindRotate)
+}
+
+// testParameters returns toy parameters: q = 2^32, N = 512, n = 32, and a
+// gadget vector of 3 levels in base 2^8.
+func testParameters() *tfhe.Parameters {
+	return tfhe.NewParameters(512, 32, 32, 8, 3, rlwe.Gaussian{Sigma: 1 << 10}, rlwe.Gaussian{Sigma: 1 << 6})
+}
+
+func testLWE(t *testing.T) {
+	params := testParameters()
+	sk := params.LWEKeyGen(negacyclic.DefaultSource)
+	mu := 1 << 30
+	phase := params.LWEPhase(sk, params.EncryptLWE(sk, mu, negacyclic.DefaultSource))
+	if dist := torusDistance(params, phase, mu); dist > 1<<14 {
+		t.Fatalf("LWE noise %d", dist)
+	}
+}
+
+func testRLWE(t *testing.T) {
+	params := testParameters()
+	sk := params.RLWEKeyGen(negacyclic.DefaultSource)
+	mu := randomTorus(params)
+	phase := params.RLWEPhase(sk, params.EncryptRLWE(sk, mu, negacyclic.DefaultSource))
+	checkNoise(t, params, mu, phase, 1<<10)
+}
+
+func testExternalProduct(t *testing.T) {
+	params := testParameters()
+	sk := params.RLWEKeyGen(negacyclic.DefaultSource)
+	mu := randomTorus(params)
+	ct := params.EncryptRLWE(sk, mu, negacyclic.DefaultSource)
+	// X^3 rotates the phase by 3 positions.
+	monomial := negacyclic.NewPolynomial(params.N)
+	monomial.Coeffs[3].SetInt64(1)
+	rgsw := params.EncryptRGSW(sk, monomial, negacyclic.DefaultSource)
+	prod := params.ExternalProduct(rgsw, ct)
+	expected := negacyclic.NewPolynomial(params.N)
+	for i, coeff := range mu.Coeffs {
+		if i+3 < params.N {
+			expected.Coeffs[i+3].Set(coeff)
+		} else {
+			expected.Coeffs[i+3-params.N].Neg(coeff)
+		}
+	}
+	checkNoise(t, params, expected, params.RLWEPhase(sk, prod), 1<<22)
+}
+
+func testCMux(t *testing.T) {
+	params := testParameters()
+	sk := params.RLWEKeyGen(negacyclic.DefaultSource)
+	mu0, mu1 := randomTorus(params), randomTorus(params)
+	ct0, ct1 := params.EncryptRLWE(sk, mu0, negacyclic.DefaultSource), params.EncryptRLWE(sk, mu1, negacyclic.DefaultSource)
+	for bit, expected := range []*negacyclic.Polynomial{mu0, mu1} {
+		selector := negacyclic.NewPolynomial(params.N)
+		selector.Coeffs[0].SetInt64(int64(bit))
+		res := params.CMux(params.EncryptRGSW(sk, selector, negacyclic.DefaultSource), ct0, ct1)
+		checkNoise(t, params, expected, params.RLWEPhase(sk, res), 1<<22)
+	}
+}
+
+// testBlindRotate evaluates x -> x^2 mod 4 on encryptions of x in [0, 4),
+// encoded in the half torus as (2x+1)·q/16, and of x + 4, encoded in the
+// other half.
+func testBlindRotate(t *testing.T) {
+	params := testParameters()
+	lweSK := params.LWEKeyGen(negacyclic.DefaultSource)
+	rlweSK := params.RLWEKeyGen(negacyclic.DefaultSource)
+	bsk := params.GenBootstrappingKey(lweSK, rlweSK, negacyclic.DefaultSource)
+	p := 4
+	outScale := 1 << (32 - 3) // q/8
+	tv := params.LookUpTable(func(j int) int {
+		x := j * p / params.N
+		return (x * x % p) * outScale
+	})
+	for x := 0; x < 2*p; x++ {
+		// The second half of the torus yields the opposite values.
+		ct := params.EncryptLWE(lweSK, (2*x+1)<<(32-4), negacyclic.DefaultSource)
+		acc := params.BlindRotate(ct, bsk, tv)
+		phase := params.RLWEPhase(rlweSK, acc)
+		got := int(phase.Coeffs[0].Int64())
+		expected := (x * x % p) * outScale
+		if x >= p {
+			expected = -expected
+		}
+		if dist := torusDistance(params, got, expected); dist > outScale/2 {
+			t.Fatalf("f(%d): expected %d, got %d", x, expected, got)
+		}
+	}
+}
+
+func randomTorus(params *tfhe.Parameters) *negacyclic.Polynomial {
+	mu := negacyclic.NewPolynomial(params.N)
+	for _, coeff := range mu.Coeffs {
+		coeff.SetInt64(rand.Int63n(1 << uint(params.LogQ)))
+	}
+	return mu
+}
+
+func torusDistance(params *tfhe.Parameters, x, y int) int {
+	d := (x - y) & (1<<uint(params.LogQ) - 1)
+	if d > 1<<uint(params.LogQ-1) {
+		d = 1<<uint(params.LogQ) - d
+	}
+	return d
+}
+
+func checkNoise(t *testing.T, params *tfhe.Parameters, expected, phase *negacyclic.Polynomial, bound int64) {
+	t.Helper()
+	noise := negacyclic.Sub(phase, expected).InfNorm(params.Q())
+	t.Logf("noise: %d bits", noise.BitLen())
+	if noise.Cmp(big.NewInt(bound)) > 0 {
+		t.Fatalf("noise %d exceeds %d", noise, bound)
+	}
+}
@@ -0,0 +1,44 @@
+package negacyclic
+
+import "math/big"
+
+// UniformBounded returns a vector of dim coefficients drawn uniformly in
+// [-γ+1, γ], from src. Each coefficient is γ - r for r uniform in [0, 2γ),
+// obtained by rejection without bias, as for the masking vectors of
+// Fiat–Shamir with aborts signatures.
+func UniformBounded(dim, gamma int, src Source) *Vector {
+	if gamma < 1 || uint64(gamma) > 1<<61 {
+		panic("UniformBounded expects 1 <= γ <= 2^61")
+	}
+	v := NewVector(dim)
+	for i := range v.Coeffs {
+		v.Coeffs[i] = gamma - randIntn(src, 2*gamma)
+	}
+	return v
+}
+
+// UniformBoundedFromSeed is UniformBounded drawn from NewSeededSource(seed,
+// nonce): the same inputs always give the same output.
+func UniformBoundedFromSeed(dim, gamma int, seed, nonce []byte) *Vector {
+	return UniformBounded(dim, gamma, NewSeededSource(seed, nonce))
+}
+
+// UniformBoundedBig is UniformBounded for a large γ, returning a polynomial of
+// given degree.
+func UniformBoundedBig(deg int, gamma *big.Int, src Source) *Polynomial {
+	if gamma.Sign() <= 0 {
+		panic("UniformBoundedBig expects γ >= 1")
+	}
+	width := new(big.Int).Lsh(gamma, 1)
+	p := PolynomialFromSlice(uniformSlice(src, deg, width))
+	for _, coeff := range p.Coeffs {
+		coeff.Sub(gamma, coeff)
+	}
+	return p
+}
+
+// UniformBoundedBigFromSeed is UniformBoundedBig drawn from
+// NewSeededSource(seed, nonce).
+func UniformBoundedBigFromSeed(deg int, gamma *big.Int, seed, nonce []byte) *Polynomial {
+	return UniformBoundedBig(deg, gamma, NewSeededSource(seed, nonce))
+}
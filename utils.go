@@ -5,8 +5,8 @@ import (
 	"math/bits"
 )
 
-// L1Distance returns the distance between `p` and `y` with respect to the
-// canonical norm, defined by the given primitive N-th complex root of unity.
+// L1Distance returns the l-1 distance between the coefficient vectors of `p`
+// and `y`.
 func L1Distance(p, y []*big.Int) *big.Int {
 	if len(p) != len(y) {
 		panic("incompatible arguments to distance")
@@ -19,10 +19,9 @@ func L1Distance(p, y []*big.Int) *big.Int {
 	return L1Norm(diff)
 }
 
-// L1Norm returns the l-1 norm of the vector formed by the evaluations of
-// the given polynomial at the set of given primitive root of unity generated
-// by `root`. Equivalently, it is the l-∞ norm of the action of the Vandermonde map
-// of these roots, on the given polynomial.
+// L1Norm returns the l-1 norm of the coefficient vector of the given
+// polynomial. Norms with respect to the canonical embedding can be computed
+// from the evaluations returned by CanonicalEmbedding.
 func L1Norm(pol []*big.Int) *big.Int {
 	res := big.NewInt(0)
 	aux := new(big.Int)
@@ -29,7 +29,7 @@ func (m *ZMultiplier) Mul(x, y *Polynomial) *Polynomial {
 		panic("bad multiply length")
 	}
 	prime := big.NewInt(int64(2 * m.N))
-	prime.Mul(prime, normInfinite(x)).Mul(prime, normInfinite(y))
+	prime.Mul(prime, x.InfNorm(nil)).Mul(prime, y.InfNorm(nil))
 	prime.Add(prime, big.NewInt(1))
 	for !prime.ProbablyPrime(32) {
 		prime.Add(prime, big.NewInt(int64(2*m.N)))
@@ -39,13 +39,3 @@ func (m *ZMultiplier) Mul(x, y *Polynomial) *Polynomial {
 	pol.Mod(prime)
 	return pol
 }
-
-func normInfinite(pol *Polynomial) *big.Int {
-	norm := new(big.Int)
-	for _, val := range pol.Coeffs {
-		if norm.CmpAbs(val) < 0 {
-			norm.Abs(val)
-		}
-	}
-	return norm
-}